@@ -51,9 +51,8 @@ resource "mongodb_process" "mdb_standalone" {
 locals {
   ops_manager_port = 8080
 }
-resource "random_string" "encryptionkey" {
-  length = 24
-  special = true
+resource "random_id" "encryptionkey" {
+  byte_length = 24
 }
 resource "random_string" "globalownerpassword" {
   length = 12
@@ -73,9 +72,9 @@ resource "mongodb_opsmanager" "opsman" {
     binary = "http://localhost:9000/mongodb-mms_4.1.8.55966.20190620T2143Z-1_x86_64.deb"
     workdir = "/opt/mongodb"
     mongo_uri = "mongodb://${mongodb_process.mdb_standalone.host.0.hostname}:${mongodb_process.mdb_standalone.mongod.0.port}/"
-    encryption_key = random_string.encryptionkey.result
+    encryption_key = random_id.encryptionkey.b64_std
     port = local.ops_manager_port
-    external_port = docker_container.mdb0-0.ports[1].external
+    api_url = "http://127.0.0.1:${docker_container.mdb0-0.ports[1].external}"
     central_url = "http://${mongodb_process.mdb_standalone.host.0.hostname}:${local.ops_manager_port}"
     register_global_owner = true
     global_owner_username = "admin"
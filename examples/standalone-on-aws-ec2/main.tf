@@ -132,9 +132,8 @@ resource "mongodb_process" "mdb_standalone" {
 
 
 # Deploy a single instance of Ops Manager
-resource "random_string" "encryptionkey" {
-  length  = 24
-  special = true
+resource "random_id" "encryptionkey" {
+  byte_length = 24
 }
 resource "random_string" "globalownerpassword" {
   length      = 12
@@ -157,7 +156,7 @@ resource "mongodb_opsmanager" "opsman" {
     binary                = "https://downloads.mongodb.com/on-prem-mms/rpm/mongodb-mms-4.0.13.50537.20190703T1029Z-1.x86_64.rpm"
     workdir               = "/opt/mongodb"
     mongo_uri             = "mongodb://${local.appdb_bind_ip}:${mongodb_process.mdb_standalone.mongod.0.port}/"
-    encryption_key        = random_string.encryptionkey.result
+    encryption_key        = random_id.encryptionkey.b64_std
     port                  = local.ops_manager_port
     external_port         = local.ops_manager_port
     central_url           = "http://${mongodb_process.mdb_standalone.host.0.hostname}:${local.ops_manager_port}"
@@ -194,5 +193,6 @@ resource "mongodb_automation_agent" "automation_agent" {
     mms_base_url      = mongodb_opsmanager.opsman.opsmanager[0].central_url
     mms_group_id      = mongodb_opsmanager.opsman.opsmanager[0].mms_group_id
     mms_agent_api_key = mongodb_opsmanager.opsman.opsmanager[0].mms_agent_api_key
+    install_method    = "package"
   }
 }
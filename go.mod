@@ -3,6 +3,8 @@ module github.com/mongodb-labs/terraform-provider-mongodb
 go 1.13
 
 require (
+	github.com/Sectorbob/mlab-ns2 v0.0.0-20171030222938-d3aa0c295a8a
+	github.com/hashicorp/go-version v1.2.0
 	github.com/hashicorp/terraform v0.12.12
 	github.com/magiconair/properties v1.8.1
 	github.com/mongodb-labs/pcgc v0.0.3
@@ -0,0 +1,562 @@
+package mongodb
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/ssh"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
+)
+
+// resourceAgent builds a resource which installs and configures the specified agent type on a remote host;
+// the agent's settings are specified in a block named after its type, using the passed schema
+func resourceAgent(agentType types.AgentType, withAgentSchema func() map[string]*schema.Schema) *schema.Resource {
+	resourceSchema := types.NewSchemaMap(WithHostSchema, withAgentSchema, WithOpsManagerAPISchema)
+
+	return &schema.Resource{
+		Create: func(data *schema.ResourceData, meta interface{}) error {
+			return resourceMdbAgentCreate(agentType, data, meta)
+		},
+		Read: func(data *schema.ResourceData, meta interface{}) error {
+			return resourceMdbAgentRead(agentType, data, meta)
+		},
+		Update: func(data *schema.ResourceData, meta interface{}) error {
+			return resourceMdbAgentUpdate(agentType, data, meta)
+		},
+		Delete: func(data *schema.ResourceData, meta interface{}) error {
+			return resourceMdbAgentDelete(agentType, data, meta)
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(util.LongCreationTimeout),
+			Read:   schema.DefaultTimeout(util.DefaultTimeout),
+			Update: schema.DefaultTimeout(util.LongCreationTimeout),
+			Delete: schema.DefaultTimeout(util.DefaultTimeout),
+		},
+		Schema: resourceSchema,
+	}
+}
+
+// If the Create callback returns with or without an error without an ID set using SetId, the resource is assumed to not be created, and no state is saved.
+// If the Create callback returns with or without an error and an ID has been set, the resource is assumed created and all state is saved with it.
+func resourceMdbAgentCreate(agentType types.AgentType, data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	// read host params
+	host := data.Get("host").([]interface{})
+	conn := types.ReadRemoteConnection(host)
+	id, err := conn.ToJSON()
+	if err != nil {
+		return err
+	}
+	data.SetId(id)
+
+	// read process config
+	agentConfig := types.ReadAgentConfig(agentType, data.Get(agentType.Name).([]interface{}))
+
+	// create a SSH connection to the remote host
+	sshClient, err := NewSSHClient(providerConfig, conn)
+	if err != nil {
+		return fmt.Errorf("could not create a SSH client: %v", err)
+	}
+
+	// create the working directory and set the appropriate permissions
+	cmd := fmt.Sprintf("bash -c \"mkdir -p %[1]s && chown $(whoami) %[1]s && chmod 0775 %[1]s\"", agentConfig.WorkDir)
+	if err := sshClient.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not create the working directory: %v", err)
+	}
+
+	// resolve the concrete version which Ops Manager serves, instead of downloading 'latest'
+	agentConfig.InstalledVersion = resolveAgentVersion(data, meta, agentConfig)
+
+	// install the agent
+	agentConfig.Platform, err = installAgent(agentConfig, sshClient, conn)
+	if err != nil {
+		return err
+	}
+	if err := setAgentConfig(data, agentConfig); err != nil {
+		return err
+	}
+
+	// configure and start the agent
+	started := time.Now()
+	if err := configureAgent(agentConfig, nil, sshClient, conn); err != nil {
+		return err
+	}
+	if agentConfig.Running {
+		if err := startAgent(agentConfig, sshClient, conn); err != nil {
+			return err
+		}
+
+		// confirm that the agent reports to Ops Manager
+		if err := registerAgent(data, meta, agentConfig, sshClient, conn, started, data.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
+	return resourceMdbAgentRead(agentType, data, meta)
+}
+
+// This callback should never modify the real resource.
+// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band).
+// Just like the destroy callback, the Read function should gracefully handle this case.
+func resourceMdbAgentRead(agentType types.AgentType, data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	host := data.Get("host").([]interface{})
+	conn := types.ReadRemoteConnection(host)
+
+	agentConfig := types.ReadAgentConfig(agentType, data.Get(agentType.Name).([]interface{}))
+
+	// create a SSH connection to the remote host
+	sshClient, err := NewSSHClient(providerConfig, conn)
+	if err != nil {
+		return fmt.Errorf("could not create a SSH client: %v", err)
+	}
+
+	// the agent was removed if its config file no longer exists
+	result := sshClient.RunCommand(conn.SudoPrefix(fmt.Sprintf("bash -c \"test -f %s && echo found || echo missing\"", agentConfig.ConfigFilename())))
+	if result.IsError() {
+		return fmt.Errorf("could not find the %s agent config: %v", agentType.Name, result)
+	}
+	if result.Stdout != "found" {
+		log.Printf("[WARN] could not find the %s agent config: %s", agentType.Name, agentConfig.ConfigFilename())
+		data.SetId("")
+		return nil
+	}
+
+	// a stopped agent is reported as drift, and restarted by the next update
+	result = ssh.NewServiceStatusChecker(sshClient)(agentConfig.BinaryFilename())
+	if result.IsError() {
+		return fmt.Errorf("could not check if the %s agent is running: %v", agentType.Name, result)
+	}
+	agentConfig.Running = result.Stdout == "started"
+	if !agentConfig.Running {
+		log.Printf("[WARN] the %s agent is not running on: %s", agentType.Name, conn.Hostname)
+	}
+
+	// read back the values managed by this resource, to detect drift
+	result = sshClient.RunCommand(conn.SudoPrefix(fmt.Sprintf("cat %s", agentConfig.ConfigFilename())))
+	if result.IsError() {
+		return fmt.Errorf("could not read the %s agent config: %v", agentType.Name, result)
+	}
+	props, err := types.NewPropertiesFile(result.Stdout)
+	if err != nil {
+		return fmt.Errorf("could not read the %s agent config: %v", agentType.Name, err)
+	}
+	if v, ok := props.GetPropertyValue(agentConfig.GetAgentConfigTag("MMSGroupID")); ok {
+		agentConfig.MMSGroupID = v
+	}
+	if v, ok := props.GetPropertyValue(agentConfig.GetAgentConfigTag("MMSAgentAPIKey")); ok {
+		agentConfig.MMSAgentAPIKey = v
+	}
+	if v, ok := agentConfig.GetBaseURL(props); ok {
+		agentConfig.MMSBaseURL = v
+	}
+	agentConfig.HTTPProxy, _ = props.GetPropertyValue(agentConfig.GetAgentConfigTag("HTTPProxy"))
+
+	// Ops Manager upgrades the agent in place, when it serves a newer version
+	version, err := readAgentVersion(agentConfig, sshClient, conn)
+	if err != nil {
+		return err
+	}
+	if agentConfig.InstalledVersion != "" && agentConfig.InstalledVersion != version {
+		log.Printf("[DEBUG] the %s agent on %s was upgraded from %s to %s", agentType.Name, conn.Hostname, agentConfig.InstalledVersion, version)
+	}
+	agentConfig.InstalledVersion = version
+
+	// refresh the last time the agent reported to Ops Manager
+	if agentConfig.AgentHostname != "" && HasOpsManagerAPI(data, meta) {
+		if agent, ok := readAgentStatus(data, meta, agentConfig); ok {
+			agentConfig.LastPing = agent.LastConf
+		}
+	}
+
+	// update the resource data
+	if err := setAgentConfig(data, agentConfig); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] updated the %s agent resource...", agentType.Name)
+	return nil
+}
+
+// If the Update callback returns with or without an error, the full state is saved.
+// If the ID becomes blank, the resource is destroyed (even within an update, though this shouldn't happen except in error scenarios).
+// Partial mode is a mode that can be enabled by a callback that tells Terraform that it is possible for partial state to occur.
+// When this mode is enabled, the provider must explicitly tell Terraform what is safe to persist and what is not.
+func resourceMdbAgentUpdate(agentType types.AgentType, data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	host := data.Get("host").([]interface{})
+	conn := types.ReadRemoteConnection(host)
+
+	agentConfig := types.ReadAgentConfig(agentType, data.Get(agentType.Name).([]interface{}))
+
+	old, _ := data.GetChange(agentType.Name)
+	oldConfig := types.ReadAgentConfig(agentType, old.([]interface{}))
+
+	// rewrite the agent's config and restart it, or start and stop it, if it is not in the desired state
+	changed := false
+	for _, key := range []string{"mms_group_id", "mms_agent_api_key", "mms_base_url", "ssl_trusted_mms_server_certificate",
+		"ssl_require_valid_mms_server_certificates", "http_proxy", "max_log_file_size", "max_log_file_duration_hrs", "overrides"} {
+		changed = changed || data.HasChange(fmt.Sprintf("%s.0.%s", agentType.Name, key))
+	}
+	if changed || agentConfig.Running != oldConfig.Running {
+		sshClient, err := NewSSHClient(providerConfig, conn)
+		if err != nil {
+			return fmt.Errorf("could not create a SSH client: %v", err)
+		}
+
+		started := time.Now()
+		if changed {
+			if err := configureAgent(agentConfig, oldConfig.Overrides, sshClient, conn); err != nil {
+				return err
+			}
+		}
+		if err := stopAgent(agentConfig, sshClient, conn); err != nil {
+			return err
+		}
+		if !agentConfig.Running {
+			return resourceMdbAgentRead(agentType, data, meta)
+		}
+		if err := startAgent(agentConfig, sshClient, conn); err != nil {
+			return err
+		}
+
+		// confirm that the agent reports to Ops Manager, using the new settings
+		if err := registerAgent(data, meta, agentConfig, sshClient, conn, started, data.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	return resourceMdbAgentRead(agentType, data, meta)
+}
+
+// If the Destroy callback returns without an error, the resource is assumed to be destroyed, and all state is removed.
+// If the Destroy callback returns with an error, the resource is assumed to still exist, and all prior state is preserved.
+// If the resource is already destroyed, this should not return an error.
+// This allows Terraform users to manually delete resources without breaking Terraform.
+func resourceMdbAgentDelete(agentType types.AgentType, data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	host := data.Get("host").([]interface{})
+	conn := types.ReadRemoteConnection(host)
+
+	agentConfig := types.ReadAgentConfig(agentType, data.Get(agentType.Name).([]interface{}))
+
+	sshClient, err := NewSSHClient(providerConfig, conn)
+	if err != nil {
+		return fmt.Errorf("could not create a SSH client: %v", err)
+	}
+
+	// stop the agent, so that it no longer reports to Ops Manager, and remove its files
+	if err := stopAgent(agentConfig, sshClient, conn); err != nil {
+		return err
+	}
+	if agentConfig.IsPackage() {
+		if err := sshClient.RunCommand(conn.SudoPrefix(agentConfig.UninstallCommand())).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not uninstall the %s agent package: %v", agentType.Name, err)
+		}
+		log.Printf("[DEBUG] uninstalled the %s agent package", agentType.Name)
+	}
+	cmd := fmt.Sprintf("rm -rf %s", agentConfig.WorkDir)
+	if err := sshClient.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not remove the %s agent: %v", agentType.Name, err)
+	}
+	log.Printf("[DEBUG] removed the %s agent from: %s", agentType.Name, agentConfig.WorkDir)
+
+	data.SetId("")
+	return nil
+}
+
+// installAgent downloads the agent from Ops Manager and installs it, either from a package or from a tar.gz archive;
+// returns the platform for which the agent was downloaded
+func installAgent(cfg types.AgentConfig, client *ssh.Client, conn types.RemoteConnection) (string, error) {
+	format := "tar.gz"
+	if cfg.IsPackage() {
+		// install the package type supported by the remote host's package manager
+		result := client.RunCommand("bash -c \"if command -v dpkg >/dev/null 2>&1; then echo deb; elif command -v rpm >/dev/null 2>&1; then echo rpm; fi\"")
+		if result.IsError() {
+			return "", fmt.Errorf("could not detect the package manager: %v", result)
+		}
+		if result.Stdout == "" {
+			return "", fmt.Errorf("could not find a supported package manager (dpkg, rpm) on: %s", conn.Hostname)
+		}
+		format = result.Stdout
+	}
+
+	// pick the build matching the remote host's architecture and distribution, unless explicitly specified
+	if cfg.Platform == "" {
+		result := client.RunCommand(types.AgentPlatformCommand)
+		if result.IsError() {
+			return "", fmt.Errorf("could not detect the platform: %v", result)
+		}
+		platform, err := types.ParseAgentPlatform(result.Stdout)
+		if err != nil {
+			return "", err
+		}
+		cfg.Platform, err = platform.Name(format)
+		if err != nil {
+			return "", fmt.Errorf("could not determine the %s agent build for %s, specify a platform: %v", cfg.Type.Name, conn.Hostname, err)
+		}
+		log.Printf("[DEBUG] detected the platform: %s", cfg.Platform)
+	}
+
+	// download the agent on the remote host
+	filename := cfg.DownloadFilename(format)
+	archive := path.Join(cfg.WorkDir, filename)
+	cmd := fmt.Sprintf("curl -fSL -o %s \"%s\"", archive, cfg.DownloadURL(filename))
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return "", fmt.Errorf("could not download the %s agent: %v", cfg.Type.Name, err)
+	}
+
+	if cfg.IsPackage() {
+		// the package creates the service user and registers the agent as a system service
+		if err := client.RunCommand(conn.SudoPrefix(cfg.InstallCommand(archive))).ErrorOrNil(); err != nil {
+			return "", fmt.Errorf("could not install the %s agent package: %v", cfg.Type.Name, err)
+		}
+		if err := client.RunCommand(conn.SudoPrefix(cfg.EnableServiceCommand())).ErrorOrNil(); err != nil {
+			return "", fmt.Errorf("could not enable the %s agent service: %v", cfg.Type.Name, err)
+		}
+		log.Printf("[DEBUG] installed the package: %s", filename)
+		return cfg.Platform, nil
+	}
+
+	// unpack the binary
+	cmd = fmt.Sprintf("tar -C %s -xvzf %s --strip 1", cfg.WorkDir, archive)
+	if err := client.RunCommand(cmd).ErrorOrNil(); err != nil {
+		return "", fmt.Errorf("could not unpack the %s agent: %v", cfg.Type.Name, err)
+	}
+	log.Printf("[DEBUG] unpacked the binary in: %s", cfg.WorkDir)
+
+	// create the service user, which is otherwise created by the package
+	cmd = fmt.Sprintf("bash -c \"id -u %[1]s >/dev/null 2>&1 || useradd --system --user-group --no-create-home --home-dir %[2]s --shell /bin/false %[1]s\"", cfg.Type.ServiceUser, cfg.WorkDir)
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return "", fmt.Errorf("could not create the %s service user: %v", cfg.Type.ServiceUser, err)
+	}
+	return cfg.Platform, nil
+}
+
+// resolveAgentVersion returns the agent version to download; 'latest' is resolved through the Ops Manager API,
+// if configured, otherwise the installed version is only known once the agent was unpacked
+func resolveAgentVersion(data *schema.ResourceData, meta interface{}, cfg types.AgentConfig) string {
+	if cfg.Version != types.LatestAgentVersion || !HasOpsManagerAPI(data, meta) {
+		return cfg.Version
+	}
+
+	apiClient, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		log.Printf("[WARN] could not resolve the latest %s agent version: %v", cfg.Type.Name, err)
+		return cfg.Version
+	}
+
+	versions, err := apiClient.GetSoftwareComponentVersions()
+	if err != nil {
+		log.Printf("[WARN] could not resolve the latest %s agent version: %v", cfg.Type.Name, err)
+		return cfg.Version
+	}
+
+	version := versions.Of(cfg.Type.Name)
+	if version == "" {
+		log.Printf("[WARN] Ops Manager did not report the latest %s agent version", cfg.Type.Name)
+		return cfg.Version
+	}
+
+	log.Printf("[DEBUG] resolved the latest %s agent version: %s", cfg.Type.Name, version)
+	return version
+}
+
+// readAgentVersion returns the version of the agent installed on the remote host
+func readAgentVersion(cfg types.AgentConfig, client *ssh.Client, conn types.RemoteConnection) (string, error) {
+	result := client.RunCommand(conn.SudoPrefix(cfg.VersionCommand()))
+	if result.IsError() {
+		return "", fmt.Errorf("could not read the %s agent version on %s: %v", cfg.Type.Name, conn.Hostname, result)
+	}
+	return types.ParseAgentVersion(result.Stdout + result.Stderr)
+}
+
+// registerAgent waits for the agent to report to Ops Manager, which fails if its API key or base URL are wrong,
+// and stores the hostname under which it reports; the check is skipped if the Ops Manager API was not configured
+func registerAgent(data *schema.ResourceData, meta interface{}, cfg types.AgentConfig, client *ssh.Client, conn types.RemoteConnection, since time.Time, timeout time.Duration) error {
+	if !HasOpsManagerAPI(data, meta) {
+		log.Printf("[WARN] the Ops Manager API was not configured, could not verify that the %s agent on %s registered", cfg.Type.Name, conn.Hostname)
+		return nil
+	}
+
+	apiClient, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	hostnames, err := readAgentHostnames(client, conn)
+	if err != nil {
+		return err
+	}
+
+	agent, err := waitForAgentRegistration(apiClient, cfg.MMSGroupID, cfg.Type.APIType, hostnames, since, timeout)
+	if err != nil {
+		return err
+	}
+
+	cfg.AgentHostname = agent.Hostname
+	cfg.LastPing = agent.LastConf
+	return setAgentConfig(data, cfg)
+}
+
+// readAgentStatus retrieves the agent's status from Ops Manager; returns false if it could not be found
+func readAgentStatus(data *schema.ResourceData, meta interface{}, cfg types.AgentConfig) (api.Agent, bool) {
+	apiClient, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		log.Printf("[WARN] could not read the %s agent's status: %v", cfg.Type.Name, err)
+		return api.Agent{}, false
+	}
+
+	agents, err := apiClient.GetAgents(cfg.MMSGroupID, cfg.Type.APIType)
+	if err != nil {
+		log.Printf("[WARN] could not read the %s agent's status: %v", cfg.Type.Name, err)
+		return api.Agent{}, false
+	}
+
+	agent, ok := findAgent(agents, []string{cfg.AgentHostname})
+	if !ok {
+		log.Printf("[WARN] the %s agent on %s is not reporting to project: %s", cfg.Type.Name, cfg.AgentHostname, cfg.MMSGroupID)
+	}
+	return agent, ok
+}
+
+// configureAgent writes the values managed by this resource into the agent's config file;
+// any keys found in previousOverrides, but not in the current overrides, are removed from the configuration
+func configureAgent(cfg types.AgentConfig, previousOverrides map[string]interface{}, client *ssh.Client, conn types.RemoteConnection) error {
+	// upload the CA which signed Ops Manager's HTTPS certificate, if specified
+	if cfg.SSLTrustedMMSServerCertificate != "" {
+		if err := uploadSecretFile(cfg.SSLTrustedMMSServerCertificate, cfg.SSLTrustedMMSServerCertificateFilename(), cfg.Type.ServiceUser, client, conn); err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] uploaded the trusted CA to: %s", cfg.SSLTrustedMMSServerCertificateFilename())
+	}
+
+	// baseUrl, ApiKey, and projectID must be set in the file along with any specified overrides
+	var baseURLErr error
+	err :=
+		updatePropertiesFile(client, conn, cfg.ConfigFilename(), func(props *types.PropertiesFile) {
+			props.SetPropertyValue(cfg.GetAgentConfigTag("MMSGroupID"), cfg.MMSGroupID)
+			props.SetComments(cfg.GetAgentConfigTag("MMSGroupID"), []string{"", commentString, ""})
+			props.SetPropertyValue(cfg.GetAgentConfigTag("MMSAgentAPIKey"), cfg.MMSAgentAPIKey)
+			baseURLErr = cfg.SetBaseURL(props)
+			if cfg.Type.LogFileKey != "" {
+				props.SetPropertyValue(cfg.Type.LogFileKey, cfg.LogFilename())
+			}
+
+			// TLS, proxy, and log rotation settings are removed from the file when no longer specified;
+			// settings which are not supported by the agent's type are skipped
+			setAgentProperty := func(fieldName string, value string, enabled bool) {
+				if key := cfg.GetAgentConfigTag(fieldName); key != "" {
+					setOptionalProperty(props, key, value, enabled)
+				}
+			}
+			setAgentProperty("SSLTrustedMMSServerCertificate", cfg.SSLTrustedMMSServerCertificateFilename(), cfg.SSLTrustedMMSServerCertificate != "")
+			setAgentProperty("SSLRequireValidMMSServerCertificates", strconv.FormatBool(cfg.SSLRequireValidMMSServerCertificates), true)
+			setAgentProperty("HTTPProxy", cfg.HTTPProxy, cfg.HTTPProxy != "")
+			setAgentProperty("MaxLogFileSize", strconv.Itoa(cfg.MaxLogFileSize), cfg.MaxLogFileSize > 0)
+			setAgentProperty("MaxLogFileDurationHrs", strconv.Itoa(cfg.MaxLogFileDurationHrs), cfg.MaxLogFileDurationHrs > 0)
+
+			for prop := range previousOverrides {
+				if _, ok := cfg.Overrides[prop]; !ok {
+					props.RemoveProperty(prop)
+				}
+			}
+			for prop, val := range cfg.Overrides {
+				props.SetPropertyValue(prop, val.(string))
+			}
+		})
+	if err != nil {
+		return fmt.Errorf("could not configure the %s agent: %v", cfg.Type.Name, err)
+	}
+	if baseURLErr != nil {
+		return fmt.Errorf("could not configure the %s agent: %v", cfg.Type.Name, baseURLErr)
+	}
+
+	// set the correct owner on all agent files
+	cmd := fmt.Sprintf("chown -R %[1]s:%[1]s %[2]s %[3]s", cfg.Type.ServiceUser, cfg.WorkDir, cfg.ConfigFilename())
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not change the owner of the %s agent files: %v", cfg.Type.Name, err)
+	}
+	return nil
+}
+
+// startAgent starts the agent and waits for it to be running
+func startAgent(cfg types.AgentConfig, client *ssh.Client, conn types.RemoteConnection) error {
+	if cfg.IsPackage() {
+		// the service is supervised by systemd or init.d, and survives reboots;
+		// it is restarted, since some packages start it as soon as they are installed, before it is configured
+		if err := client.RunCommand(conn.SudoPrefix(cfg.ServiceCommand("restart"))).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not start the %s agent: %v", cfg.Type.Name, err)
+		}
+	} else {
+		// archive installs are run in the background, as the service user
+		cmd := fmt.Sprintf("su -s /bin/sh %[1]s -c 'nohup %[2]s --config=%[3]s >> %[4]s/%[5]s-agent-fatal.log 2>&1 &' && sleep 1",
+			cfg.Type.ServiceUser, cfg.BinaryFilename(), cfg.ConfigFilename(), cfg.WorkDir, cfg.Type.Name)
+		if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not start the %s agent: %v", cfg.Type.Name, err)
+		}
+	}
+
+	// wait for the agent to start
+	if err := ssh.WaitForService(ssh.NewServiceStatusChecker(client), cfg.BinaryFilename()); err != nil {
+		return fmt.Errorf("failed waiting for the %s agent to start: %v", cfg.Type.Name, err)
+	}
+	log.Printf("[DEBUG] confirmed the %s agent is running using this config: %s", cfg.Type.Name, cfg.ConfigFilename())
+	return nil
+}
+
+// stopAgent stops the agent, if it is running
+func stopAgent(cfg types.AgentConfig, client *ssh.Client, conn types.RemoteConnection) error {
+	if cfg.IsPackage() {
+		// do not fail if the service was already stopped or removed
+		result := client.RunCommand(conn.SudoPrefix(cfg.ServiceCommand("stop")))
+		if result.IsError() {
+			log.Printf("[WARN] could not stop the %s agent, it may have already been stopped: %v", cfg.Type.Name, result)
+		}
+		log.Printf("[DEBUG] stopped the %s agent on: %s", cfg.Type.Name, conn.Hostname)
+		return nil
+	}
+
+	// bracket the first character of the executable's name, so that the pattern does not match this command's shell
+	dir, file := path.Split(cfg.BinaryFilename())
+	pattern := fmt.Sprintf("%s[%s]%s", dir, file[:1], file[1:])
+	cmd := fmt.Sprintf("bash -c \"pkill -f '%[1]s'; for i in $(seq 1 30); do pgrep -f '%[1]s' >/dev/null || exit 0; sleep 1; done; pkill -9 -f '%[1]s'; exit 0\"", pattern)
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not stop the %s agent: %v", cfg.Type.Name, err)
+	}
+	log.Printf("[DEBUG] stopped the %s agent on: %s", cfg.Type.Name, conn.Hostname)
+	return nil
+}
+
+// setAgentConfig stores the passed agent configuration in the resource data, in the block named after its type
+func setAgentConfig(data *schema.ResourceData, cfg types.AgentConfig) error {
+	resourceData := make(map[string]interface{})
+	resourceData["mms_base_url"] = cfg.MMSBaseURL
+	resourceData["mms_group_id"] = cfg.MMSGroupID
+	resourceData["mms_agent_api_key"] = cfg.MMSAgentAPIKey
+	resourceData["version"] = cfg.Version
+	resourceData["workdir"] = cfg.WorkDir
+	resourceData["install_method"] = cfg.InstallMethod
+	resourceData["platform"] = cfg.Platform
+	resourceData["ssl_trusted_mms_server_certificate"] = cfg.SSLTrustedMMSServerCertificate
+	resourceData["ssl_require_valid_mms_server_certificates"] = cfg.SSLRequireValidMMSServerCertificates
+	resourceData["http_proxy"] = cfg.HTTPProxy
+	resourceData["max_log_file_size"] = cfg.MaxLogFileSize
+	resourceData["max_log_file_duration_hrs"] = cfg.MaxLogFileDurationHrs
+	resourceData["overrides"] = cfg.Overrides
+	resourceData["installed_version"] = cfg.InstalledVersion
+	resourceData["agent_hostname"] = cfg.AgentHostname
+	resourceData["last_ping"] = cfg.LastPing
+	resourceData["running"] = cfg.Running
+	return data.Set(cfg.Type.Name, []map[string]interface{}{resourceData})
+}
@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/ssh"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+)
+
+// agentClockSkew tolerates differences between the local clock and the Ops Manager host's clock, when comparing ping times
+const agentClockSkew = time.Minute
+
+// readAgentHostnames returns the names under which an agent running on the remote host may report to Ops Manager
+func readAgentHostnames(client *ssh.Client, conn types.RemoteConnection) ([]string, error) {
+	hostnames := []string{conn.Hostname}
+
+	// agents report the host's fully qualified name, which may differ from the address used to connect to it
+	result := client.RunCommand("bash -c \"hostname -f 2>/dev/null; hostname\"")
+	if result.IsError() {
+		return nil, fmt.Errorf("could not determine the hostname: %v", result)
+	}
+	for _, hostname := range strings.Fields(result.Stdout) {
+		hostnames = append(hostnames, hostname)
+	}
+	return hostnames, nil
+}
+
+// findAgent returns the agent reporting under any of the specified hostnames; returns false if none were found
+func findAgent(agents []api.Agent, hostnames []string) (api.Agent, bool) {
+	for _, agent := range agents {
+		for _, hostname := range hostnames {
+			if strings.EqualFold(agent.Hostname, hostname) {
+				return agent, true
+			}
+		}
+	}
+	return api.Agent{}, false
+}
+
+// waitForAgentRegistration waits until an agent of the specified type, running on one of the specified hosts,
+// pings Ops Manager after the specified time
+func waitForAgentRegistration(client *api.Client, projectID string, agentType string, hostnames []string, since time.Time, timeout time.Duration) (api.Agent, error) {
+	var agent api.Agent
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		agents, err := client.GetAgents(projectID, agentType)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		found, ok := findAgent(agents, hostnames)
+		if !ok {
+			return resource.RetryableError(fmt.Errorf("no %s agent reported from %v", agentType, hostnames))
+		}
+
+		// ignore agents which were previously registered from the same host, but have not pinged since
+		if lastPing, err := time.Parse(time.RFC3339, found.LastConf); err == nil && lastPing.Before(since.Add(-agentClockSkew)) {
+			return resource.RetryableError(fmt.Errorf("the %s agent on %s last pinged at %s", agentType, found.Hostname, found.LastConf))
+		}
+
+		agent = found
+		return nil
+	})
+	if err != nil {
+		return api.Agent{}, fmt.Errorf("the agent did not register with Ops Manager; check its API key and base URL: %v", err)
+	}
+
+	log.Printf("[DEBUG] the %s agent on %s registered with project: %s", agentType, agent.Hostname, projectID)
+	return agent, nil
+}
@@ -0,0 +1,33 @@
+package api
+
+// Agent represents an agent which reports to a project
+type Agent struct {
+	TypeName  string `json:"typeName"`
+	Hostname  string `json:"hostname"`
+	ConfCount int    `json:"confCount"`
+	LastConf  string `json:"lastConf"`
+	StateName string `json:"stateName"`
+}
+
+// agentsResponse a page of agents
+type agentsResponse struct {
+	Results    []Agent `json:"results"`
+	TotalCount int     `json:"totalCount"`
+}
+
+// GetAgents retrieves all the agents of the specified type (e.g. AUTOMATION) which report to the project identified by projectID
+// https://docs.opsmanager.mongodb.com/current/reference/api/agents-get-by-type/
+func (c *Client) GetAgents(projectID string, agentType string) ([]Agent, error) {
+	var agents []Agent
+	for page := 1; ; page++ {
+		var result agentsResponse
+		if err := c.getJSON(c.resolver.Of("/groups/%s/agents/%s?pageNum=%d&itemsPerPage=500", projectID, agentType, page), &result); err != nil {
+			return nil, err
+		}
+
+		agents = append(agents, result.Results...)
+		if len(result.Results) == 0 || len(agents) >= result.TotalCount {
+			return agents, nil
+		}
+	}
+}
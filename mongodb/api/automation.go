@@ -0,0 +1,35 @@
+package api
+
+// AutomationConfig a project's automation config, decoded as a generic map;
+// unlike opsmanager.AutomationConfig, it preserves all the fields which it does not explicitly model when it is written back
+type AutomationConfig map[string]interface{}
+
+// List returns the list stored under the specified key, or an empty list if it is not set
+func (c AutomationConfig) List(key string) []interface{} {
+	if v, ok := c[key].([]interface{}); ok {
+		return v
+	}
+	return []interface{}{}
+}
+
+// Map returns the map stored under the specified key, or an empty map if it is not set
+func (c AutomationConfig) Map(key string) map[string]interface{} {
+	if v, ok := c[key].(map[string]interface{}); ok {
+		return v
+	}
+	return make(map[string]interface{})
+}
+
+// ReadAutomationConfig retrieves the automation config of the project identified by projectID
+// https://docs.opsmanager.mongodb.com/current/reference/api/automation-config/#get-the-automation-configuration
+func (c *Client) ReadAutomationConfig(projectID string) (AutomationConfig, error) {
+	result := make(AutomationConfig)
+	err := c.getJSON(c.resolver.Of("/groups/%s/automationConfig", projectID), &result)
+	return result, err
+}
+
+// WriteAutomationConfig replaces the automation config of the project identified by projectID
+// https://docs.opsmanager.mongodb.com/current/reference/api/automation-config/#update-the-automation-configuration
+func (c *Client) WriteAutomationConfig(projectID string, config AutomationConfig) error {
+	return c.putJSON(c.resolver.Of("/groups/%s/automationConfig", projectID), config, nil)
+}
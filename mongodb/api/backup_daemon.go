@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/url"
+)
+
+// DaemonMachine identifies the host and head directory of a Backup Daemon
+type DaemonMachine struct {
+	Machine           string `json:"machine"`
+	HeadRootDirectory string `json:"headRootDirectory"`
+}
+
+// DaemonConfig represents the configuration of a Backup Daemon
+type DaemonConfig struct {
+	ID                          string        `json:"id,omitempty"`
+	Machine                     DaemonMachine `json:"machine"`
+	AssignmentEnabled           bool          `json:"assignmentEnabled"`
+	BackupJobsEnabled           bool          `json:"backupJobsEnabled"`
+	Configured                  bool          `json:"configured"`
+	GarbageCollectionEnabled    bool          `json:"garbageCollectionEnabled"`
+	ResourceUsageEnabled        bool          `json:"resourceUsageEnabled"`
+	RestoreQueryableJobsEnabled bool          `json:"restoreQueryableJobsEnabled"`
+	NumWorkers                  int           `json:"numWorkers,omitempty"`
+	Labels                      []string      `json:"labels,omitempty"`
+}
+
+// GetBackupDaemonConfig retrieves the configuration of the Backup Daemon running on the specified machine
+// https://docs.opsmanager.mongodb.com/current/reference/api/admin/backup/daemonConfigs/get-one-backup-daemon-configuration/
+func (c *Client) GetBackupDaemonConfig(machine string) (DaemonConfig, error) {
+	var result DaemonConfig
+	err := c.getJSON(c.resolver.Of("/admin/backup/daemon/configs/%s", url.PathEscape(machine)), &result)
+	return result, err
+}
+
+// UpdateBackupDaemonConfig configures (and enables) the Backup Daemon identified by config.Machine
+// https://docs.opsmanager.mongodb.com/current/reference/api/admin/backup/daemonConfigs/update-one-backup-daemon-configuration/
+func (c *Client) UpdateBackupDaemonConfig(config DaemonConfig) (DaemonConfig, error) {
+	var result DaemonConfig
+	err := c.putJSON(c.resolver.Of("/admin/backup/daemon/configs/%s", url.PathEscape(config.Machine.Machine)), config, &result)
+	return result, err
+}
+
+// DeleteBackupDaemonConfig removes the configuration of the Backup Daemon running on the specified machine
+// https://docs.opsmanager.mongodb.com/current/reference/api/admin/backup/daemonConfigs/delete-one-backup-daemon-configuration/
+func (c *Client) DeleteBackupDaemonConfig(machine string) error {
+	return c.delete(c.resolver.Of("/admin/backup/daemon/configs/%s", url.PathEscape(machine)))
+}
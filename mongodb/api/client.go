@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mongodb-labs/pcgc/pkg/httpclient"
+	"github.com/mongodb-labs/pcgc/pkg/opsmanager"
+)
+
+// Client wraps an Ops Manager API client, adding the endpoints which are not (yet) implemented by pcgc
+type Client struct {
+	opsmanager.Client
+	resolver httpclient.URLResolver
+}
+
+// Config holds the parameters used to connect to the Ops Manager API
+type Config struct {
+	// BaseURL the Ops Manager URL (e.g., https://opsmanager.example.com:8443)
+	BaseURL string
+	// Username a username or programmatic API public key; requests are not authenticated if left empty
+	Username string
+	// APIKey the user's API key, or a programmatic API private key
+	APIKey string
+	// CACert a PEM encoded certificate authority, used to validate Ops Manager's certificate
+	CACert string
+	// Retries the number of times a request is retried while Ops Manager is unavailable
+	Retries int
+}
+
+// NewClient builds a new Ops Manager API client using the specified configuration
+func NewClient(cfg Config) (*Client, error) {
+	basicClient, err := newRetryingClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := httpclient.NewURLResolverWithPrefix(cfg.BaseURL, opsmanager.PublicAPIPrefix)
+	return &Client{
+		Client:   opsmanager.NewClient(opsmanager.WithResolver(resolver), opsmanager.WithHTTPClient(basicClient)),
+		resolver: resolver,
+	}, nil
+}
+
+// Error represents a failed API call, retaining the HTTP status code, if a response was received
+type Error struct {
+	StatusCode int
+	Err        error
+}
+
+// Error implementation of the error interface
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// IsNotFound returns true if the passed error was caused by the API not finding the requested resource
+func IsNotFound(err error) bool {
+	if apiErr, ok := err.(*Error); ok {
+		return apiErr.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// newError wraps a failed HTTP response as an *Error
+func newError(resp httpclient.HTTPResponse) error {
+	apiErr := &Error{Err: resp.Err}
+	if resp.Response != nil {
+		apiErr.StatusCode = resp.Response.StatusCode
+	}
+	if apiErr.Err == nil {
+		apiErr.Err = fmt.Errorf("unexpected HTTP status code: %d", apiErr.StatusCode)
+	}
+	return apiErr
+}
+
+// getJSON retrieves the specified URL and decodes the response into result
+func (c *Client) getJSON(url string, result interface{}) error {
+	return decode(c.GetJSON(url), result)
+}
+
+// postJSON sends the specified body to the passed URL and decodes the response into result
+func (c *Client) postJSON(url string, body interface{}, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return decode(c.PostJSON(url, bytes.NewReader(data)), result)
+}
+
+// putJSON sends the specified body to the passed URL and decodes the response into result
+func (c *Client) putJSON(url string, body interface{}, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return decode(c.PutJSON(url, bytes.NewReader(data)), result)
+}
+
+// patchJSON sends the specified body to the passed URL and decodes the response into result
+func (c *Client) patchJSON(url string, body interface{}, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return decode(c.PatchJSON(url, bytes.NewReader(data)), result)
+}
+
+// delete deletes the resource at the specified URL
+func (c *Client) delete(url string) error {
+	resp := c.Delete(url)
+	defer httpclient.CloseResponseBodyIfNotNil(resp)
+	if resp.IsError() {
+		return newError(resp)
+	}
+	return nil
+}
+
+// decode checks the passed response for errors and decodes its body into result, if one was specified
+func decode(resp httpclient.HTTPResponse, result interface{}) error {
+	defer httpclient.CloseResponseBodyIfNotNil(resp)
+	if resp.IsError() {
+		return newError(resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	// some endpoints do not return a body
+	if err := json.NewDecoder(resp.Response.Body).Decode(result); err != nil && err != io.EOF {
+		return fmt.Errorf("could not decode the API response: %v", err)
+	}
+	return nil
+}
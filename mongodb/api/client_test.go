@@ -0,0 +1,143 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetProject_unit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/v1.0/groups/5d1" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "5d1", "name": "team-a", "orgId": "5d0"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{BaseURL: server.URL, Username: "user", APIKey: "key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	project, err := client.GetProject("5d1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project.Name != "team-a" || project.OrgID != "5d0" {
+		t.Errorf("unexpected project: %+v", project)
+	}
+}
+
+func TestGetProjectNotFound_unit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorCode": "GROUP_NOT_FOUND"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{BaseURL: server.URL, Username: "user", APIKey: "key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = client.GetProject("5d1")
+	if !IsNotFound(err) {
+		t.Errorf("expected a not found error, got: %v", err)
+	}
+}
+
+func TestRetryWhileUnavailable_unit(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "5d1", "name": "team-a"}`))
+	}))
+	defer server.Close()
+
+	client, err := newRetryingClient(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.backoff = time.Millisecond
+
+	resp := client.GetJSON(server.URL)
+	if resp.IsError() {
+		t.Fatalf("unexpected error: %v", resp.Err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got: %d", attempts)
+	}
+}
+
+func TestGetAgentsPaginates_unit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/v1.0/groups/5d1/agents/AUTOMATION" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("pageNum") == "1" {
+			_, _ = w.Write([]byte(`{"results": [{"hostname": "a.example.com"}], "totalCount": 2}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"hostname": "b.example.com"}], "totalCount": 2}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{BaseURL: server.URL, Username: "user", APIKey: "key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	agents, err := client.GetAgents("5d1", "AUTOMATION")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(agents) != 2 || agents[1].Hostname != "b.example.com" {
+		t.Errorf("unexpected agents: %+v", agents)
+	}
+}
+
+func TestRetryNonIdempotentRequests_unit(t *testing.T) {
+	attempts := 0
+	status := http.StatusBadGateway
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client, err := newRetryingClient(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.backoff = time.Millisecond
+	client.retries = 2
+
+	// the POST may have been processed before the gateway failed, and is not resent
+	if resp := client.PostJSON(server.URL, strings.NewReader("{}")); !resp.IsError() {
+		t.Fatal("expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got: %d", attempts)
+	}
+
+	// the POST was rejected without being processed, and is resent
+	attempts = 0
+	status = http.StatusTooManyRequests
+	if resp := client.PostJSON(server.URL, strings.NewReader("{}")); !resp.IsError() {
+		t.Fatal("expected an error")
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got: %d", attempts)
+	}
+}
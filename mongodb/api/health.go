@@ -0,0 +1,6 @@
+package api
+
+// CheckHealth returns nil if Ops Manager is up and can reach its application database
+func (c *Client) CheckHealth() error {
+	return c.getJSON(c.resolver.OfUnprefixed("/monitor/health"), nil)
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Sectorbob/mlab-ns2/gae/ns/digest"
+	"github.com/mongodb-labs/pcgc/pkg/httpclient"
+)
+
+const (
+	// contentTypeJSON defines the JSON content type
+	contentTypeJSON = "application/json; charset=UTF-8"
+
+	// preferJSON signals that we are accepting JSON responses, but do not reject non-JSON data
+	preferJSON = "application/json;q=0.9, */*;q=0.8"
+
+	// defaultRetries the number of times a failed request is retried
+	defaultRetries = 5
+
+	// defaultBackoff the initial delay between retries, doubled after each attempt
+	defaultBackoff = 1 * time.Second
+)
+
+// retryingClient implements httpclient.BasicClient, adding support for custom certificate authorities
+// and retrying requests which failed because Ops Manager was unavailable
+type retryingClient struct {
+	client  *http.Client
+	retries int
+	backoff time.Duration
+}
+
+// newRetryingClient builds a new HTTP client using the specified configuration
+func newRetryingClient(cfg Config) (*retryingClient, error) {
+	timeouts := httpclient.NewDefaultTimeouts()
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout: timeouts.DialTimeout,
+		}).DialContext,
+		ExpectContinueTimeout: timeouts.ExpectContinueTimeout,
+		IdleConnTimeout:       timeouts.IdleConnectionTimeout,
+		ResponseHeaderTimeout: timeouts.ResponseHeaderTimeout,
+		TLSHandshakeTimeout:   timeouts.TLSHandshakeTimeout,
+	}
+
+	// trust the specified certificate authority
+	if cfg.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cfg.CACert)) {
+			return nil, fmt.Errorf("could not parse the Ops Manager CA certificate")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	// authenticate all requests, if credentials were specified
+	var roundTripper http.RoundTripper = transport
+	if cfg.Username != "" {
+		roundTripper = &digest.Transport{Username: cfg.Username, Password: cfg.APIKey, Transport: transport}
+	}
+
+	retries := cfg.Retries
+	if retries == 0 {
+		retries = defaultRetries
+	}
+
+	return &retryingClient{
+		client:  &http.Client{Transport: roundTripper, Timeout: timeouts.GlobalTimeout},
+		retries: retries,
+		backoff: defaultBackoff,
+	}, nil
+}
+
+// GetJSON retrieves the specified URL
+func (c *retryingClient) GetJSON(url string) httpclient.HTTPResponse {
+	return c.request(http.MethodGet, url, nil)
+}
+
+// PostJSON executes a POST request, sending the specified body, encoded as JSON, to the passed URL
+func (c *retryingClient) PostJSON(url string, body io.Reader) httpclient.HTTPResponse {
+	return c.request(http.MethodPost, url, body)
+}
+
+// PatchJSON executes a PATCH request, sending the specified body, encoded as JSON, to the passed URL
+func (c *retryingClient) PatchJSON(url string, body io.Reader) httpclient.HTTPResponse {
+	return c.request(http.MethodPatch, url, body)
+}
+
+// PutJSON executes a PUT request, sending the specified body, encoded as JSON, to the passed URL
+func (c *retryingClient) PutJSON(url string, body io.Reader) httpclient.HTTPResponse {
+	return c.request(http.MethodPut, url, body)
+}
+
+// Delete executes a DELETE request
+func (c *retryingClient) Delete(url string) httpclient.HTTPResponse {
+	return c.request(http.MethodDelete, url, nil)
+}
+
+// request issues a request, retrying with exponential backoff while Ops Manager is unavailable
+func (c *retryingClient) request(verb string, url string, body io.Reader) httpclient.HTTPResponse {
+	// buffer the body, so that it can be resent
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = ioutil.ReadAll(body); err != nil {
+			return httpclient.HTTPResponse{Err: err}
+		}
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		resp := c.send(verb, url, data)
+		if !isRetryable(verb, resp) || attempt >= c.retries {
+			return resp
+		}
+
+		log.Printf("[DEBUG] retrying %s %s in %v, after: %v", verb, url, backoff, resp.Err)
+		httpclient.CloseResponseBodyIfNotNil(resp)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// send issues a single request and validates the response's status code
+func (c *retryingClient) send(verb string, url string, data []byte) (resp httpclient.HTTPResponse) {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(verb, url, body)
+	if err != nil {
+		resp.Err = err
+		return
+	}
+	req.Header.Add("Accept", preferJSON)
+	if data != nil {
+		// only set the request content type if a body was passed
+		req.Header.Add("Content-Type", contentTypeJSON)
+	}
+
+	resp.Response, resp.Err = c.client.Do(req)
+	if resp.Err != nil {
+		return
+	}
+
+	// any 2xx status code is a success
+	if resp.Response.StatusCode >= 200 && resp.Response.StatusCode < 300 {
+		return
+	}
+
+	// otherwise, read the error details and close the body, but retain the response, for its status code
+	details, _ := ioutil.ReadAll(resp.Response.Body)
+	httpclient.CloseResponseBodyIfNotNil(resp)
+	resp.Err = fmt.Errorf("failed to execute %s request to %s; status: %s, details: %s", verb, url, resp.Response.Status, bytes.TrimSpace(details))
+	return
+}
+
+// isRetryable returns true if the request failed because Ops Manager could not be reached, or was temporarily unavailable;
+// non-idempotent requests (POST, PATCH) are only retried if they were known not to be processed, since resending a request
+// whose response was lost could otherwise create duplicates
+func isRetryable(verb string, resp httpclient.HTTPResponse) bool {
+	if !resp.IsError() {
+		return false
+	}
+
+	idempotent := verb == http.MethodGet || verb == http.MethodPut || verb == http.MethodDelete
+	if resp.Response == nil {
+		// the request never reached the server if the connection could not be established
+		return idempotent || isDialError(resp.Err)
+	}
+
+	switch resp.Response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// isDialError returns true if the error occurred while connecting to the server, before any data was sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
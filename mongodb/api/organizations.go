@@ -0,0 +1,42 @@
+package api
+
+import (
+	"github.com/mongodb-labs/pcgc/pkg/opsmanager"
+)
+
+// Organization represents an Ops Manager organization
+type Organization struct {
+	ID    string            `json:"id,omitempty"`
+	Name  string            `json:"name"`
+	Links []opsmanager.Link `json:"links,omitempty"`
+}
+
+// CreateOrganization creates a new organization
+// https://docs.opsmanager.mongodb.com/current/reference/api/organizations/organization-create-one/
+func (c *Client) CreateOrganization(name string) (Organization, error) {
+	var result Organization
+	err := c.postJSON(c.resolver.Of("/orgs"), Organization{Name: name}, &result)
+	return result, err
+}
+
+// GetOrganization retrieves the organization identified by orgID
+// https://docs.opsmanager.mongodb.com/current/reference/api/organizations/organization-get-one/
+func (c *Client) GetOrganization(orgID string) (Organization, error) {
+	var result Organization
+	err := c.getJSON(c.resolver.Of("/orgs/%s", orgID), &result)
+	return result, err
+}
+
+// RenameOrganization changes the name of the organization identified by orgID
+// https://docs.opsmanager.mongodb.com/current/reference/api/organizations/organization-rename/
+func (c *Client) RenameOrganization(orgID string, name string) (Organization, error) {
+	var result Organization
+	err := c.patchJSON(c.resolver.Of("/orgs/%s", orgID), Organization{Name: name}, &result)
+	return result, err
+}
+
+// DeleteOrganization deletes the organization identified by orgID
+// https://docs.opsmanager.mongodb.com/current/reference/api/organizations/organization-delete-one/
+func (c *Client) DeleteOrganization(orgID string) error {
+	return c.delete(c.resolver.Of("/orgs/%s", orgID))
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"github.com/mongodb-labs/pcgc/pkg/opsmanager"
+)
+
+// GetProject retrieves the project identified by projectID
+// https://docs.opsmanager.mongodb.com/current/reference/api/groups/get-one-group-by-id/
+func (c *Client) GetProject(projectID string) (opsmanager.ProjectResponse, error) {
+	var result opsmanager.ProjectResponse
+	err := c.getJSON(c.resolver.Of("/groups/%s", projectID), &result)
+	return result, err
+}
+
+// RenameProject changes the name of the project identified by projectID
+// https://docs.opsmanager.mongodb.com/current/reference/api/groups/update-one-group/
+func (c *Client) RenameProject(projectID string, name string) (opsmanager.ProjectResponse, error) {
+	var result opsmanager.ProjectResponse
+	err := c.patchJSON(c.resolver.Of("/groups/%s", projectID), map[string]string{"name": name}, &result)
+	return result, err
+}
+
+// RemoveProject deletes the project identified by projectID
+// https://docs.opsmanager.mongodb.com/current/reference/api/groups/delete-one-group/
+func (c *Client) RemoveProject(projectID string) error {
+	return c.delete(c.resolver.Of("/groups/%s", projectID))
+}
@@ -0,0 +1,30 @@
+package api
+
+// SoftwareComponentVersions the versions of the agents and tools served by Ops Manager
+type SoftwareComponentVersions struct {
+	AutomationVersion string `json:"automationVersion"`
+	BackupVersion     string `json:"backupVersion"`
+	MonitoringVersion string `json:"monitoringVersion"`
+}
+
+// GetSoftwareComponentVersions retrieves the versions of the agents and tools served by Ops Manager
+// https://docs.opsmanager.mongodb.com/current/reference/api/software-components/
+func (c *Client) GetSoftwareComponentVersions() (SoftwareComponentVersions, error) {
+	var result SoftwareComponentVersions
+	err := c.getJSON(c.resolver.Of("/softwareComponents/versions/"), &result)
+	return result, err
+}
+
+// Of returns the version of the specified agent (automation, monitoring, backup)
+func (v SoftwareComponentVersions) Of(agent string) string {
+	switch agent {
+	case "automation":
+		return v.AutomationVersion
+	case "monitoring":
+		return v.MonitoringVersion
+	case "backup":
+		return v.BackupVersion
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"github.com/mongodb-labs/pcgc/pkg/opsmanager"
+)
+
+// Team represents an Ops Manager team
+type Team struct {
+	ID        string            `json:"id,omitempty"`
+	Name      string            `json:"name"`
+	Usernames []string          `json:"usernames,omitempty"`
+	Links     []opsmanager.Link `json:"links,omitempty"`
+}
+
+// teamUsersResponse API response for the GetTeamUsers() call
+type teamUsersResponse struct {
+	Results    []opsmanager.UserResponse `json:"results"`
+	TotalCount int                       `json:"totalCount"`
+}
+
+// CreateTeam creates a new team in the specified organization, with the specified members
+// https://docs.opsmanager.mongodb.com/current/reference/api/teams/teams-create-one/
+func (c *Client) CreateTeam(orgID string, name string, usernames []string) (Team, error) {
+	var result Team
+	err := c.postJSON(c.resolver.Of("/orgs/%s/teams", orgID), Team{Name: name, Usernames: usernames}, &result)
+	return result, err
+}
+
+// GetTeam retrieves the team identified by teamID
+// https://docs.opsmanager.mongodb.com/current/reference/api/teams/teams-get-one-by-id/
+func (c *Client) GetTeam(orgID string, teamID string) (Team, error) {
+	var result Team
+	err := c.getJSON(c.resolver.Of("/orgs/%s/teams/%s", orgID, teamID), &result)
+	return result, err
+}
+
+// RenameTeam changes the name of the team identified by teamID
+// https://docs.opsmanager.mongodb.com/current/reference/api/teams/teams-rename-one/
+func (c *Client) RenameTeam(orgID string, teamID string, name string) (Team, error) {
+	var result Team
+	err := c.patchJSON(c.resolver.Of("/orgs/%s/teams/%s", orgID, teamID), map[string]string{"name": name}, &result)
+	return result, err
+}
+
+// DeleteTeam deletes the team identified by teamID
+// https://docs.opsmanager.mongodb.com/current/reference/api/teams/teams-delete-one/
+func (c *Client) DeleteTeam(orgID string, teamID string) error {
+	return c.delete(c.resolver.Of("/orgs/%s/teams/%s", orgID, teamID))
+}
+
+// GetTeamUsers retrieves all members of the team identified by teamID
+// https://docs.opsmanager.mongodb.com/current/reference/api/teams/teams-get-all-users/
+func (c *Client) GetTeamUsers(orgID string, teamID string) ([]opsmanager.UserResponse, error) {
+	var result teamUsersResponse
+	err := c.getJSON(c.resolver.Of("/orgs/%s/teams/%s/users", orgID, teamID), &result)
+	return result.Results, err
+}
+
+// AddTeamUsers adds the specified users (by ID) to the team identified by teamID
+// https://docs.opsmanager.mongodb.com/current/reference/api/teams/teams-add-user/
+func (c *Client) AddTeamUsers(orgID string, teamID string, userIDs []string) error {
+	request := make([]map[string]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		request = append(request, map[string]string{"id": id})
+	}
+	return c.postJSON(c.resolver.Of("/orgs/%s/teams/%s/users", orgID, teamID), request, nil)
+}
+
+// RemoveTeamUser removes the specified user (by ID) from the team identified by teamID
+// https://docs.opsmanager.mongodb.com/current/reference/api/teams/teams-remove-user/
+func (c *Client) RemoveTeamUser(orgID string, teamID string, userID string) error {
+	return c.delete(c.resolver.Of("/orgs/%s/teams/%s/users/%s", orgID, teamID, userID))
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/url"
+
+	"github.com/mongodb-labs/pcgc/pkg/opsmanager"
+)
+
+// UserRequest represents a user, along with its roles, as sent to the API
+type UserRequest struct {
+	opsmanager.User
+
+	Roles []opsmanager.UserRole `json:"roles"`
+}
+
+// CreateUser creates a new user, with the specified roles
+// https://docs.opsmanager.mongodb.com/current/reference/api/user-create/
+func (c *Client) CreateUser(user UserRequest) (opsmanager.UserResponse, error) {
+	var result opsmanager.UserResponse
+	err := c.postJSON(c.resolver.Of("/users"), user, &result)
+	return result, err
+}
+
+// GetUser retrieves the user identified by userID
+// https://docs.opsmanager.mongodb.com/current/reference/api/user-get-by-id/
+func (c *Client) GetUser(userID string) (opsmanager.UserResponse, error) {
+	var result opsmanager.UserResponse
+	err := c.getJSON(c.resolver.Of("/users/%s", userID), &result)
+	return result, err
+}
+
+// GetUserByName retrieves the user identified by username
+// https://docs.opsmanager.mongodb.com/current/reference/api/user-get-by-name/
+func (c *Client) GetUserByName(username string) (opsmanager.UserResponse, error) {
+	var result opsmanager.UserResponse
+	err := c.getJSON(c.resolver.Of("/users/byName/%s", url.PathEscape(username)), &result)
+	return result, err
+}
+
+// UpdateUser updates the user identified by userID; the passed roles replace all existing roles
+// https://docs.opsmanager.mongodb.com/current/reference/api/user-update/
+func (c *Client) UpdateUser(userID string, user UserRequest) (opsmanager.UserResponse, error) {
+	var result opsmanager.UserResponse
+	err := c.patchJSON(c.resolver.Of("/users/%s", userID), user, &result)
+	return result, err
+}
+
+// DeleteUser removes the user identified by userID
+// https://docs.opsmanager.mongodb.com/current/reference/api/user-delete/
+func (c *Client) DeleteUser(userID string) error {
+	return c.delete(c.resolver.Of("/users/%s", userID))
+}
+
+// AddUserAccessList allows the user identified by userID to call the API from the specified CIDR blocks
+// https://docs.opsmanager.mongodb.com/current/reference/api/whitelist-add-entries/
+func (c *Client) AddUserAccessList(userID string, cidrBlocks []string) error {
+	entries := make([]map[string]string, 0, len(cidrBlocks))
+	for _, cidrBlock := range cidrBlocks {
+		entries = append(entries, map[string]string{"cidrBlock": cidrBlock})
+	}
+	return c.postJSON(c.resolver.Of("/users/%s/whitelist", userID), entries, nil)
+}
+
+// DeleteUserAccessList no longer allows the user identified by userID to call the API from the specified CIDR block
+// https://docs.opsmanager.mongodb.com/current/reference/api/whitelist-delete-one/
+func (c *Client) DeleteUserAccessList(userID string, cidrBlock string) error {
+	return c.delete(c.resolver.Of("/users/%s/whitelist/%s", userID, url.PathEscape(cidrBlock)))
+}
@@ -0,0 +1,297 @@
+package mongodb
+
+import (
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+)
+
+const (
+	// defaultAuthMechanism the authentication mechanism enabled if none were specified
+	defaultAuthMechanism = "SCRAM-SHA-256"
+
+	// authSchemaVersion the authentication schema used by MongoDB 3.0+
+	authSchemaVersion = 5
+)
+
+// applyDeployment replaces the processes and replica sets previously defined by a deployment resource
+// with their current definition; any other parts of the automation config are left unchanged
+func applyDeployment(config api.AutomationConfig, previous []types.DeploymentProcess, current []types.DeploymentProcess) {
+	managed := make(map[string]bool)
+	replicaSets := make(map[string]bool)
+	for _, p := range append(append([]types.DeploymentProcess{}, previous...), current...) {
+		managed[p.Name] = true
+		if p.ReplicaSet != "" {
+			replicaSets[p.ReplicaSet] = true
+		}
+	}
+
+	// index the existing processes by name, retaining the ones which are not managed by this resource
+	existing := make(map[string]map[string]interface{})
+	processes := make([]interface{}, 0)
+	for _, item := range config.List("processes") {
+		process := item.(map[string]interface{})
+		name, _ := process["name"].(string)
+		if managed[name] {
+			existing[name] = process
+			continue
+		}
+		processes = append(processes, process)
+	}
+
+	// add the current processes, updating the fields managed by this resource in place
+	for _, p := range current {
+		process, ok := existing[p.Name]
+		if !ok {
+			process = make(map[string]interface{})
+		}
+		setProcess(process, p)
+		processes = append(processes, process)
+	}
+	config["processes"] = processes
+
+	// rebuild the members of each replica set which is (or was) defined by this resource
+	sets := make([]interface{}, 0)
+	for _, item := range config.List("replicaSets") {
+		rs := item.(map[string]interface{})
+		id, _ := rs["_id"].(string)
+		if !replicaSets[id] {
+			sets = append(sets, rs)
+			continue
+		}
+		delete(replicaSets, id)
+		if setReplicaSetMembers(rs, managed, current) {
+			sets = append(sets, rs)
+		}
+	}
+	for _, p := range current {
+		if !replicaSets[p.ReplicaSet] {
+			continue
+		}
+		delete(replicaSets, p.ReplicaSet)
+		rs := map[string]interface{}{"_id": p.ReplicaSet, "protocolVersion": "1"}
+		setReplicaSetMembers(rs, managed, current)
+		sets = append(sets, rs)
+	}
+	config["replicaSets"] = sets
+}
+
+// definedProcessNames returns the names of all processes defined in the automation config
+func definedProcessNames(config api.AutomationConfig) []string {
+	names := make([]string, 0)
+	for _, item := range config.List("processes") {
+		process := item.(map[string]interface{})
+		if name, ok := process["name"].(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// setProcess sets the fields managed by a deployment resource on a process defined in the automation config
+func setProcess(process map[string]interface{}, p types.DeploymentProcess) {
+	process["name"] = p.Name
+	process["processType"] = "mongod"
+	process["hostname"] = p.Hostname
+	process["version"] = p.Version
+	process["authSchemaVersion"] = authSchemaVersion
+	process["disabled"] = false
+	process["manualMode"] = false
+	if p.FeatureCompatibilityVersion != "" {
+		process["featureCompatibilityVersion"] = p.FeatureCompatibilityVersion
+	}
+	if _, ok := process["logRotate"]; !ok {
+		process["logRotate"] = map[string]interface{}{"sizeThresholdMB": 1000, "timeThresholdHrs": 24}
+	}
+
+	args := childMap(process, "args2_6")
+	childMap(args, "net")["port"] = p.Port
+	childMap(args, "storage")["dbPath"] = p.DBPath
+	systemLog := childMap(args, "systemLog")
+	systemLog["destination"] = "file"
+	systemLog["path"] = p.LogPath
+	if p.ReplicaSet != "" {
+		childMap(args, "replication")["replSetName"] = p.ReplicaSet
+	} else {
+		delete(args, "replication")
+	}
+}
+
+// setReplicaSetMembers rebuilds the members of a replica set from the current processes, retaining the IDs of existing members
+// and any members which are not managed by this resource; returns false if the replica set has no members left
+func setReplicaSetMembers(rs map[string]interface{}, managed map[string]bool, current []types.DeploymentProcess) bool {
+	id, _ := rs["_id"].(string)
+
+	existing := make(map[string]map[string]interface{})
+	members := make([]interface{}, 0)
+	nextID := 0
+	for _, item := range asList(rs["members"]) {
+		member := item.(map[string]interface{})
+		host, _ := member["host"].(string)
+		if memberID := asInt(member["_id"]); memberID >= nextID {
+			nextID = memberID + 1
+		}
+		if managed[host] {
+			existing[host] = member
+			continue
+		}
+		members = append(members, member)
+	}
+
+	for _, p := range current {
+		if p.ReplicaSet != id {
+			continue
+		}
+
+		member, ok := existing[p.Name]
+		if !ok {
+			member = map[string]interface{}{"_id": nextID, "host": p.Name, "slaveDelay": 0}
+			nextID++
+		}
+		member["priority"] = p.Priority
+		member["votes"] = p.Votes
+		member["arbiterOnly"] = p.ArbiterOnly
+		member["hidden"] = p.Hidden
+		members = append(members, member)
+	}
+
+	rs["members"] = members
+	return len(members) > 0
+}
+
+// disableProcesses marks the named processes as disabled, which signals the agents to shut them down
+func disableProcesses(config api.AutomationConfig, names []string) {
+	disabled := make(map[string]bool)
+	for _, name := range names {
+		disabled[name] = true
+	}
+
+	for _, item := range config.List("processes") {
+		process := item.(map[string]interface{})
+		if name, _ := process["name"].(string); disabled[name] {
+			process["disabled"] = true
+		}
+	}
+}
+
+// applyDeploymentAuth enables authentication using the specified settings or, if nil, disables it
+func applyDeploymentAuth(config api.AutomationConfig, auth *types.DeploymentAuth) {
+	cfg := config.Map("auth")
+	config["auth"] = cfg
+
+	if auth == nil {
+		cfg["disabled"] = true
+		cfg["deploymentAuthMechanisms"] = []string{}
+		cfg["autoAuthMechanisms"] = []string{}
+		return
+	}
+
+	mechanisms := auth.Mechanisms
+	if len(mechanisms) == 0 {
+		mechanisms = []string{defaultAuthMechanism}
+	}
+	cfg["disabled"] = false
+	cfg["autoUser"] = auth.AutoUser
+	cfg["autoPwd"] = auth.AutoPassword
+	cfg["key"] = auth.Key
+	cfg["keyfile"] = auth.Keyfile
+	cfg["deploymentAuthMechanisms"] = mechanisms
+	cfg["autoAuthMechanisms"] = mechanisms
+	cfg["autoAuthMechanism"] = mechanisms[0]
+}
+
+// readDeploymentAuth reads back the deployment's authentication settings; returns false if authentication is disabled
+func readDeploymentAuth(config api.AutomationConfig) (types.DeploymentAuth, bool) {
+	cfg := config.Map("auth")
+	if disabled, _ := cfg["disabled"].(bool); disabled {
+		return types.DeploymentAuth{}, false
+	}
+
+	auth := types.DeploymentAuth{}
+	auth.Mechanisms, _ = types.ReadStringList(cfg, "deploymentAuthMechanisms")
+	if len(auth.Mechanisms) == 0 {
+		return types.DeploymentAuth{}, false
+	}
+	auth.AutoUser, _ = cfg["autoUser"].(string)
+	auth.AutoPassword, _ = cfg["autoPwd"].(string)
+	auth.Key, _ = cfg["key"].(string)
+	auth.Keyfile, _ = cfg["keyfile"].(string)
+	return auth, true
+}
+
+// readDeploymentProcess reads back a process defined in the automation config, including its replica set membership;
+// returns false if the process does not exist
+func readDeploymentProcess(config api.AutomationConfig, name string) (types.DeploymentProcess, bool) {
+	for _, item := range config.List("processes") {
+		process := item.(map[string]interface{})
+		if process["name"] != name {
+			continue
+		}
+
+		p := types.DeploymentProcess{Name: name}
+		p.Hostname, _ = process["hostname"].(string)
+		p.Version, _ = process["version"].(string)
+		p.FeatureCompatibilityVersion, _ = process["featureCompatibilityVersion"].(string)
+		args := childMap(process, "args2_6")
+		p.Port = asInt(childMap(args, "net")["port"])
+		p.DBPath, _ = childMap(args, "storage")["dbPath"].(string)
+		p.LogPath, _ = childMap(args, "systemLog")["path"].(string)
+		if rs, ok := args["replication"].(map[string]interface{}); ok {
+			p.ReplicaSet, _ = rs["replSetName"].(string)
+		}
+
+		// the member settings are stored in the replica set definition
+		for _, item := range config.List("replicaSets") {
+			rs := item.(map[string]interface{})
+			if rs["_id"] != p.ReplicaSet {
+				continue
+			}
+			for _, m := range asList(rs["members"]) {
+				member := m.(map[string]interface{})
+				if member["host"] != name {
+					continue
+				}
+				p.Priority = asFloat(member["priority"])
+				p.Votes = asInt(member["votes"])
+				p.ArbiterOnly, _ = member["arbiterOnly"].(bool)
+				p.Hidden, _ = member["hidden"].(bool)
+			}
+		}
+		return p, true
+	}
+	return types.DeploymentProcess{}, false
+}
+
+// childMap returns the map stored under the specified key, creating it if it does not exist
+func childMap(parent map[string]interface{}, key string) map[string]interface{} {
+	if v, ok := parent[key].(map[string]interface{}); ok {
+		return v
+	}
+	child := make(map[string]interface{})
+	parent[key] = child
+	return child
+}
+
+// asList converts a decoded JSON array to a list
+func asList(v interface{}) []interface{} {
+	if list, ok := v.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{}
+}
+
+// asInt converts a decoded JSON number to an int
+func asInt(v interface{}) int {
+	return int(asFloat(v))
+}
+
+// asFloat converts a decoded JSON number to a float64
+func asFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,100 @@
+package mongodb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+)
+
+func TestApplyDeployment_unit(t *testing.T) {
+	var config api.AutomationConfig
+	err := json.Unmarshal([]byte(`{
+		"version": 3,
+		"processes": [
+			{"name": "other", "hostname": "other.example.com"},
+			{"name": "rs0-0", "hostname": "a.example.com", "args2_6": {"net": {"port": 27017, "bindIp": "0.0.0.0"}}}
+		],
+		"replicaSets": [
+			{"_id": "rs0", "members": [{"_id": 3, "host": "rs0-0", "priority": 1, "votes": 1}]}
+		]
+	}`), &config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	previous := []types.DeploymentProcess{
+		{Name: "rs0-0", Hostname: "a.example.com", Port: 27017, ReplicaSet: "rs0", Priority: 1, Votes: 1},
+	}
+	current := []types.DeploymentProcess{
+		{Name: "rs0-0", Hostname: "a.example.com", Port: 27017, Version: "4.2.1", ReplicaSet: "rs0", Priority: 2, Votes: 1},
+		{Name: "rs0-1", Hostname: "b.example.com", Port: 27017, Version: "4.2.1", ReplicaSet: "rs0", Priority: 1, Votes: 1},
+	}
+	applyDeployment(config, previous, current)
+
+	if n := len(config.List("processes")); n != 3 {
+		t.Fatalf("expected 3 processes, got: %d", n)
+	}
+
+	// unmanaged fields are preserved
+	process := config.List("processes")[1].(map[string]interface{})
+	if bindIP := childMap(childMap(process, "args2_6"), "net")["bindIp"]; bindIP != "0.0.0.0" {
+		t.Errorf("expected the bindIp to be preserved, got: %v", bindIP)
+	}
+
+	// existing members retain their IDs, and new members are assigned the next available one
+	p, ok := readDeploymentProcess(config, "rs0-0")
+	if !ok || p.Priority != 2 || p.Version != "4.2.1" {
+		t.Errorf("unexpected process: %+v", p)
+	}
+	members := asList(config.List("replicaSets")[0].(map[string]interface{})["members"])
+	if len(members) != 2 || asInt(members[0].(map[string]interface{})["_id"]) != 3 || asInt(members[1].(map[string]interface{})["_id"]) != 4 {
+		t.Errorf("unexpected replica set members: %v", members)
+	}
+
+	// removing all managed processes also removes their replica set
+	applyDeployment(config, current, nil)
+	if n := len(config.List("processes")); n != 1 {
+		t.Errorf("expected 1 process, got: %d", n)
+	}
+	if n := len(config.List("replicaSets")); n != 0 {
+		t.Errorf("expected no replica sets, got: %d", n)
+	}
+}
+
+func TestReadDeploymentAuth_unit(t *testing.T) {
+	config := make(api.AutomationConfig)
+	if _, ok := readDeploymentAuth(config); ok {
+		t.Error("expected authentication to be disabled")
+	}
+
+	applyDeploymentAuth(config, &types.DeploymentAuth{AutoUser: "mms-automation", AutoPassword: "secret", Key: "key", Keyfile: "/keyfile"})
+	auth, ok := readDeploymentAuth(config)
+	if !ok {
+		t.Fatal("expected authentication to be enabled")
+	}
+	if len(auth.Mechanisms) != 1 || auth.Mechanisms[0] != defaultAuthMechanism {
+		t.Errorf("unexpected mechanisms: %v", auth.Mechanisms)
+	}
+	if auth.AutoUser != "mms-automation" || auth.AutoPassword != "secret" || auth.Key != "key" || auth.Keyfile != "/keyfile" {
+		t.Errorf("unexpected auth: %+v", auth)
+	}
+
+	applyDeploymentAuth(config, nil)
+	if _, ok := readDeploymentAuth(config); ok {
+		t.Error("expected authentication to be disabled")
+	}
+}
+
+func TestDefinedProcessNames_unit(t *testing.T) {
+	config := api.AutomationConfig{}
+	if names := definedProcessNames(config); len(names) != 0 {
+		t.Errorf("expected no processes, got: %v", names)
+	}
+
+	applyDeployment(config, nil, []types.DeploymentProcess{{Name: "rs0-0", Hostname: "a.example.com", Port: 27017, ReplicaSet: "rs0"}})
+	if names := definedProcessNames(config); len(names) != 1 || names[0] != "rs0-0" {
+		t.Errorf("unexpected processes: %v", names)
+	}
+}
@@ -79,8 +79,9 @@ func (mdb *MongoDB) SaveToTempFile(path string) (*os.File, error) {
 	buff := bytes.NewBuffer(make([]byte, 0, len(data)))
 
 	// write a comment header
-	_, err = buff.WriteString(commentString + "\n")
-	util.PanicOnNonNilErr(err)
+	if _, err = buff.WriteString(commentString + "\n"); err != nil {
+		return nil, err
+	}
 
 	// then write the data
 	var byteLength int
@@ -101,7 +102,9 @@ func (mdb *MongoDB) SaveToTempFile(path string) (*os.File, error) {
 // LoadFromFile loads a MongoDB config from the specified file
 func LoadFromFile(path string) (*MongoDB, error) {
 	raw, err := ioutil.ReadFile(path)
-	util.PanicOnNonNilErr(err)
+	if err != nil {
+		return nil, err
+	}
 
 	config := NewMongoDBConfig()
 	err = yaml.Unmarshal(raw, config)
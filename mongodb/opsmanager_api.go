@@ -0,0 +1,50 @@
+package mongodb
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+)
+
+// NewOpsManagerAPIClient returns an Ops Manager API client, built from the resource's 'opsmanager_api' configuration
+// or, if not specified, the client shared through the provider configuration
+func NewOpsManagerAPIClient(data *schema.ResourceData, meta interface{}) (*api.Client, error) {
+	if list, ok := data.GetOk("opsmanager_api"); ok {
+		return newOpsManagerAPIClient(types.ReadOpsManagerAPIConfig(list.([]interface{})))
+	}
+
+	providerConfig := meta.(ProviderConfig)
+	if providerConfig.OpsManagerAPI == nil {
+		return nil, fmt.Errorf("the Ops Manager API was not configured; specify an 'opsmanager_api' block on the resource or the provider")
+	}
+	return providerConfig.OpsManagerAPI, nil
+}
+
+// HasOpsManagerAPI returns true if the Ops Manager API was configured, either on the resource or on the provider
+func HasOpsManagerAPI(data *schema.ResourceData, meta interface{}) bool {
+	if _, ok := data.GetOk("opsmanager_api"); ok {
+		return true
+	}
+	return meta.(ProviderConfig).OpsManagerAPI != nil
+}
+
+// newOpsManagerAPIClient builds a new Ops Manager API client from the specified configuration
+func newOpsManagerAPIClient(cfg types.OpsManagerAPIConfig) (*api.Client, error) {
+	username, apiKey := cfg.Credentials()
+	if username == "" || apiKey == "" {
+		return nil, fmt.Errorf("the Ops Manager API requires either 'username' and 'api_key', or 'public_key' and 'private_key'")
+	}
+
+	client, err := api.NewClient(api.Config{
+		BaseURL:  cfg.BaseURL,
+		Username: username,
+		APIKey:   apiKey,
+		CACert:   cfg.CACert,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create an Ops Manager API client for %s: %v", cfg.BaseURL, err)
+	}
+	return client, nil
+}
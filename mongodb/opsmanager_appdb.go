@@ -0,0 +1,121 @@
+package mongodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/ssh"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
+)
+
+// minAppDBVersion the oldest MongoDB version which can be used as the Ops Manager application database
+const minAppDBVersion = "3.6.0"
+
+// appDBStatusMarker prefixes the line holding the application database's status, which may be surrounded by shell output
+const appDBStatusMarker = "appdb-status:"
+
+// appDBStatusScript connects to the application database and prints its version and replica set status, as JSON;
+// it is passed to the shell through stdin, so that the credentials in mongo_uri are not visible in the remote process list
+const appDBStatusScript = `var db = connect(%s);
+var status;
+try { status = db.adminCommand({replSetGetStatus: 1}); } catch (e) { status = {ok: 0, errmsg: e.message}; }
+print("` + appDBStatusMarker + `" + JSON.stringify({version: db.version(), ok: status.ok, set: status.set || "", errmsg: status.errmsg || ""}));
+`
+
+// appDBStatus the output of appDBStatusScript
+type appDBStatus struct {
+	Version string  `json:"version"`
+	OK      float64 `json:"ok"`
+	Set     string  `json:"set"`
+	ErrMsg  string  `json:"errmsg"`
+}
+
+// verifyApplicationDatabase checks that the application database can be used by Ops Manager, from the host on which it is installed;
+// the authentication, replica set, and version checks require the mongo shell (mongosh or mongo) to be installed on that host,
+// and are skipped if it is not
+func verifyApplicationDatabase(cfg types.OpsManagerConfig, client *ssh.Client, conn types.RemoteConnection) error {
+	hosts, err := util.ParseMongoURIHosts(cfg.MongoURI)
+	if err != nil {
+		return fmt.Errorf("invalid mongo_uri: %v", err)
+	}
+
+	// check that all the application database hosts can be reached
+	for _, host := range hosts {
+		i := strings.LastIndex(host, ":")
+		cmd := fmt.Sprintf("timeout 10 bash -c '</dev/tcp/%s/%s' >/dev/null 2>&1 && echo open || echo closed", strings.Trim(host[:i], "[]"), host[i+1:])
+		result := client.RunCommand(cmd)
+		if result.IsError() || result.Stdout != "open" {
+			return withStartupLog(fmt.Errorf("the application database host %s cannot be reached from %s", host, conn.Hostname), cfg, client, conn)
+		}
+	}
+	log.Printf("[DEBUG] reached all application database hosts: %v", hosts)
+
+	result := client.RunCommand("bash -c \"command -v mongosh || command -v mongo\"")
+	if result.IsError() || result.Stdout == "" {
+		log.Printf("[WARN] neither mongosh nor mongo were found on %s, skipping the application database version and replica set checks", conn.Hostname)
+		return nil
+	}
+	shell := result.Stdout
+
+	// connecting with the shell also verifies the credentials included in mongo_uri
+	uri, err := json.Marshal(cfg.MongoURI)
+	if err != nil {
+		return err
+	}
+	script := fmt.Sprintf(appDBStatusScript, uri)
+	result = client.RunCommandWithInput(fmt.Sprintf("%s --nodb --quiet", shell), strings.NewReader(script))
+	output := strings.Replace(result.Stdout+"\n"+result.Stderr, cfg.MongoURI, util.RedactMongoURI(cfg.MongoURI), -1)
+	status, ok := parseAppDBStatus(result.Stdout)
+	if result.IsError() || !ok {
+		return withStartupLog(fmt.Errorf("could not connect to the application database %s: %s", util.RedactMongoURI(cfg.MongoURI), strings.TrimSpace(output)), cfg, client, conn)
+	}
+
+	// verify the minimum version
+	current, err := version.NewVersion(status.Version)
+	if err != nil {
+		return fmt.Errorf("could not parse the application database version (%s): %v", status.Version, err)
+	}
+	if current.LessThan(version.Must(version.NewVersion(minAppDBVersion))) {
+		return fmt.Errorf("the application database runs MongoDB %s, but Ops Manager requires %s or newer", status.Version, minAppDBVersion)
+	}
+
+	// a replica set was requested, but the application database is not (yet) a healthy replica set
+	if strings.Contains(cfg.MongoURI, "replicaSet=") && status.OK != 1 {
+		return withStartupLog(fmt.Errorf("the application database is not a healthy replica set: %s", status.ErrMsg), cfg, client, conn)
+	}
+
+	log.Printf("[DEBUG] verified the application database (MongoDB %s, replica set: %q)", status.Version, status.Set)
+	return nil
+}
+
+// parseAppDBStatus finds and parses the status printed by appDBStatusScript; returns false if it was not printed,
+// which happens if the shell could not connect or authenticate
+func parseAppDBStatus(output string) (appDBStatus, bool) {
+	var status appDBStatus
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, appDBStatusMarker) {
+			continue
+		}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, appDBStatusMarker)), &status); err != nil {
+			log.Printf("[WARN] could not parse the application database status (%s): %v", line, err)
+			return status, false
+		}
+		return status, true
+	}
+	return status, false
+}
+
+// withStartupLog appends the last lines of the Ops Manager startup log, if it exists, to the passed error
+func withStartupLog(err error, cfg types.OpsManagerConfig, client *ssh.Client, conn types.RemoteConnection) error {
+	result := client.RunCommand(conn.SudoPrefix(fmt.Sprintf("tail -n 50 %s", cfg.StartupLogFilename())))
+	if result.IsError() || result.Stdout == "" {
+		return err
+	}
+
+	return fmt.Errorf("%v\nlast lines of %s:\n%s", err, cfg.StartupLogFilename(), result.Stdout)
+}
@@ -0,0 +1,23 @@
+package mongodb
+
+import (
+	"testing"
+)
+
+func TestParseAppDBStatus_unit(t *testing.T) {
+	output := `Current Mongosh Log ID: 5f1d
+appdb-status:{"version":"4.2.8","ok":1,"set":"rs0","errmsg":""}
+bye`
+	status, ok := parseAppDBStatus(output)
+	if !ok {
+		t.Fatal("could not find the status")
+	}
+	if status.Version != "4.2.8" || status.OK != 1 || status.Set != "rs0" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	// the status is not printed if the shell could not connect
+	if _, ok := parseAppDBStatus("Error: Authentication failed.\nbye"); ok {
+		t.Error("expected no status")
+	}
+}
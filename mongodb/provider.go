@@ -1,23 +1,37 @@
 package mongodb
 
 import (
+	"context"
+
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/ssh"
 	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
 )
 
 // Provider for MongoDB resources
 func Provider() terraform.ResourceProvider {
 	providerSchema, _ := getMergedProviderSchema()
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: providerSchema,
 		ResourcesMap: map[string]*schema.Resource{
-			"mongodb_process":          resourceMdbProcess(),
-			"mongodb_opsmanager":       resourceMdbOpsManager(),
-			"mongodb_automation_agent": resourceAutomationAgent(),
+			"mongodb_process":                  resourceMdbProcess(),
+			"mongodb_opsmanager":               resourceMdbOpsManager(),
+			"mongodb_automation_agent":         resourceAutomationAgent(),
+			"mongodb_monitoring_agent":         resourceMonitoringAgent(),
+			"mongodb_backup_agent":             resourceBackupAgent(),
+			"mongodb_opsmanager_backup_daemon": resourceBackupDaemon(),
+			"mongodb_opsmanager_project":       resourceProject(),
+			"mongodb_opsmanager_organization":  resourceOrganization(),
+			"mongodb_opsmanager_team":          resourceTeam(),
+			"mongodb_opsmanager_user":          resourceUser(),
+			"mongodb_opsmanager_deployment":    resourceDeployment(),
 		},
-		ConfigureFunc: providerConfigure,
 	}
+	provider.ConfigureFunc = func(data *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(data, provider.StopContext())
+	}
+	return provider
 }
 
 // getMergedProviderSchema defines all the components of our Provider schema
@@ -25,16 +39,34 @@ func getMergedProviderSchema() (map[string]*schema.Schema, error) {
 	return types.StrictUnion(
 		types.SSHBastionSchema(),
 		types.SSHAgentSchema(),
+		types.OpsManagerAPISchema(),
 	)
 }
 
-// providerConfigure configures the provider by parsing the passed resource data
-func providerConfigure(data *schema.ResourceData) (interface{}, error) {
+// providerConfigure configures the provider by parsing the passed resource data;
+// pooled SSH clients are closed once the passed context is done
+func providerConfigure(data *schema.ResourceData, stopCtx context.Context) (interface{}, error) {
 	bastion := types.ReadSSHBastionSchema(data)
 	agent := types.ReadSSHAgentSchema(data)
 
-	return ProviderConfig{
+	providerConfig := ProviderConfig{
 		Bastion: bastion,
 		Agent:   agent,
-	}, nil
+		SSHPool: ssh.NewPool(),
+	}
+	go func() {
+		<-stopCtx.Done()
+		providerConfig.SSHPool.Close()
+	}()
+
+	// build a shared Ops Manager API client, if credentials were specified
+	if cfg, ok := types.ReadOpsManagerAPISchema(data); ok {
+		client, err := newOpsManagerAPIClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		providerConfig.OpsManagerAPI = client
+	}
+
+	return providerConfig, nil
 }
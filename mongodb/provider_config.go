@@ -1,6 +1,7 @@
 package mongodb
 
 import (
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
 	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/ssh"
 )
 
@@ -8,6 +9,14 @@ import (
 type ProviderConfig struct {
 	ssh.Bastion
 	ssh.Agent
+
+	// OpsManagerAPI shared Ops Manager API client, used by resources which do not define their own 'opsmanager_api' block;
+	// nil if the provider was not configured with API credentials
+	OpsManagerAPI *api.Client
+
+	// SSHPool shares SSH clients between all resources targeting the same host;
+	// if nil, each operation creates its own client
+	SSHPool *ssh.Pool
 }
 
 // WithProviderConfig helper for passing provider configuration to the SSH client via a *ssh.Connection
@@ -16,6 +25,7 @@ func WithProviderConfig(pc *ProviderConfig) func(params *ssh.Connection) error {
 		params.BastionUser = pc.BastionUser
 		params.BastionHost = pc.BastionHost
 		params.BastionPort = pc.BastionPort
+		params.BastionPassword = pc.BastionPassword
 		params.BastionPrivateKey = pc.BastionPrivateKey
 		params.BastionHostKey = pc.BastionHostKey
 
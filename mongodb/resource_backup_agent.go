@@ -0,0 +1,11 @@
+package mongodb
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+)
+
+// resourceBackupAgent installs the standalone backup agent, for Ops Manager versions which predate the unified automation agent
+func resourceBackupAgent() *schema.Resource {
+	return resourceAgent(types.BackupAgent, WithBackupAgentSchema)
+}
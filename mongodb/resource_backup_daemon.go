@@ -0,0 +1,275 @@
+package mongodb
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/ssh"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
+)
+
+func resourceBackupDaemon() *schema.Resource {
+	resourceSchema := types.NewSchemaMap(WithHostSchema, WithBackupDaemonSchema, WithOpsManagerAPISchema)
+
+	return &schema.Resource{
+		Create: resourceMdbBackupDaemonCreate,
+		Read:   resourceMdbBackupDaemonRead,
+		Update: resourceMdbBackupDaemonUpdate,
+		Delete: resourceMdbBackupDaemonDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(util.LongCreationTimeout),
+			Read:   schema.DefaultTimeout(util.DefaultTimeout),
+			Update: schema.DefaultTimeout(util.LongCreationTimeout),
+			Delete: schema.DefaultTimeout(util.DefaultTimeout),
+		},
+		Schema: resourceSchema,
+	}
+}
+
+// If the Create callback returns with or without an error without an ID set using SetId, the resource is assumed to not be created, and no state is saved.
+// If the Create callback returns with or without an error and an ID has been set, the resource is assumed created and all state is saved with it.
+func resourceMdbBackupDaemonCreate(data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	// fail early, if the Ops Manager API was not configured
+	apiClient, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	// read host params
+	host := data.Get("host").([]interface{})
+	conn := types.ReadRemoteConnection(host)
+	id, err := conn.ToJSON()
+	if err != nil {
+		return err
+	}
+	data.SetId(id)
+
+	// read the daemon config
+	daemon := data.Get("backup_daemon").([]interface{})
+	daemonConfig := types.ReadBackupDaemonConfig(daemon)
+
+	// the Backup Daemon runs as part of Ops Manager, which shares the application database and the encryption key
+	if err := installOpsManager(providerConfig, conn, daemonConfig.OpsManagerConfig(), data.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	// create a SSH connection to the remote host
+	client, err := NewSSHClient(providerConfig, conn)
+	if err != nil {
+		return fmt.Errorf("could not create a SSH client: %v", err)
+	}
+
+	// create the head directory
+	if err := ensureHeadDirectory(daemonConfig, client, conn); err != nil {
+		return err
+	}
+
+	// determine the name under which the daemon registers itself with Ops Manager
+	if daemonConfig.Machine == "" {
+		result := client.RunCommand("hostname -f")
+		if result.IsError() {
+			return fmt.Errorf("could not determine the hostname: %v", result)
+		}
+		daemonConfig.Machine = result.Stdout
+	}
+
+	// enable the daemon
+	if err := configureBackupDaemon(apiClient, daemonConfig, data.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	if err := setBackupDaemonConfig(data, daemonConfig); err != nil {
+		return err
+	}
+
+	return resourceMdbBackupDaemonRead(data, meta)
+}
+
+// This callback should never modify the real resource.
+// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band).
+// Just like the destroy callback, the Read function should gracefully handle this case.
+func resourceMdbBackupDaemonRead(data *schema.ResourceData, meta interface{}) error {
+	daemon := data.Get("backup_daemon").([]interface{})
+	daemonConfig := types.ReadBackupDaemonConfig(daemon)
+
+	apiClient, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	apiConfig, err := apiClient.GetBackupDaemonConfig(daemonConfig.Machine)
+	if api.IsNotFound(err) {
+		log.Printf("[WARN] could not find the Backup Daemon configuration for: %s", daemonConfig.Machine)
+		data.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read the Backup Daemon configuration: %v", err)
+	}
+
+	// update the resource data; Ops Manager may report the head directory with a trailing slash
+	daemonConfig.HeadDirectory = types.NormalizeHeadDirectory(daemonConfig.HeadDirectory, apiConfig.Machine.HeadRootDirectory)
+	daemonConfig.NumWorkers = apiConfig.NumWorkers
+	if err := setBackupDaemonConfig(data, daemonConfig); err != nil {
+		return err
+	}
+
+	log.Print("[DEBUG] updated the Backup Daemon resource...")
+	return nil
+}
+
+// If the Update callback returns with or without an error, the full state is saved.
+// If the ID becomes blank, the resource is destroyed (even within an update, though this shouldn't happen except in error scenarios).
+// Partial mode is a mode that can be enabled by a callback that tells Terraform that it is possible for partial state to occur.
+// When this mode is enabled, the provider must explicitly tell Terraform what is safe to persist and what is not.
+func resourceMdbBackupDaemonUpdate(data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	host := data.Get("host").([]interface{})
+	conn := types.ReadRemoteConnection(host)
+
+	old, current := data.GetChange("backup_daemon")
+	oldConfig := types.ReadBackupDaemonConfig(old.([]interface{}))
+	daemonConfig := types.ReadBackupDaemonConfig(current.([]interface{}))
+
+	// create the new head directory, before the daemon is pointed to it
+	if data.HasChange("backup_daemon.0.head_directory") {
+		client, err := NewSSHClient(providerConfig, conn)
+		if err != nil {
+			return fmt.Errorf("could not create a SSH client: %v", err)
+		}
+		if err := ensureHeadDirectory(daemonConfig, client, conn); err != nil {
+			return err
+		}
+	}
+
+	// rewrite Ops Manager's properties files (including the backup.daemon.* properties) and HTTPS certificates, and restart it
+	if data.HasChange("backup_daemon.0.overrides") || data.HasChange("backup_daemon.0.central_url") ||
+		data.HasChange("backup_daemon.0.mongo_uri") || data.HasChange("backup_daemon.0.port") ||
+		data.HasChange("backup_daemon.0.head_directory") || data.HasChange("backup_daemon.0.num_workers") ||
+		data.HasChange("backup_daemon.0.https_pem_key") || data.HasChange("backup_daemon.0.https_pem_key_password") ||
+		data.HasChange("backup_daemon.0.https_ca") || data.HasChange("backup_daemon.0.https_port") {
+		if err := reconfigureOpsManager(providerConfig, conn, daemonConfig.OpsManagerConfig(), oldConfig.Overrides, data.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	// reconfigure the daemon
+	if data.HasChange("backup_daemon.0.head_directory") || data.HasChange("backup_daemon.0.num_workers") {
+		apiClient, err := NewOpsManagerAPIClient(data, meta)
+		if err != nil {
+			return err
+		}
+
+		if err := configureBackupDaemon(apiClient, daemonConfig, data.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	return resourceMdbBackupDaemonRead(data, meta)
+}
+
+// If the Destroy callback returns without an error, the resource is assumed to be destroyed, and all state is removed.
+// If the Destroy callback returns with an error, the resource is assumed to still exist, and all prior state is preserved.
+// If the resource is already destroyed, this should not return an error.
+// This allows Terraform users to manually delete resources without breaking Terraform.
+func resourceMdbBackupDaemonDelete(data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	host := data.Get("host").([]interface{})
+	conn := types.ReadRemoteConnection(host)
+
+	daemon := data.Get("backup_daemon").([]interface{})
+	daemonConfig := types.ReadBackupDaemonConfig(daemon)
+
+	// remove the daemon's configuration from Ops Manager
+	apiClient, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	err = apiClient.DeleteBackupDaemonConfig(daemonConfig.Machine)
+	if err != nil && !api.IsNotFound(err) {
+		return fmt.Errorf("could not remove the Backup Daemon configuration: %v", err)
+	}
+
+	if err := uninstallOpsManager(providerConfig, conn, daemonConfig.OpsManagerConfig()); err != nil {
+		return err
+	}
+
+	data.SetId("")
+	return nil
+}
+
+// ensureHeadDirectory creates the Backup Daemon's head directory
+func ensureHeadDirectory(cfg types.BackupDaemonConfig, client *ssh.Client, conn types.RemoteConnection) error {
+	cmd := fmt.Sprintf("bash -c \"mkdir -p %[1]s && chown mongodb-mms:mongodb-mms %[1]s && chmod 0750 %[1]s\"", cfg.HeadDirectory)
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not create the head directory: %v", err)
+	}
+	return nil
+}
+
+// configureBackupDaemon enables the Backup Daemon through the admin API, retrying until the daemon has registered itself with Ops Manager
+func configureBackupDaemon(client *api.Client, cfg types.BackupDaemonConfig, timeout time.Duration) error {
+	daemonConfig := newDaemonConfig(cfg)
+
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		if _, err := client.UpdateBackupDaemonConfig(daemonConfig); err != nil {
+			log.Printf("[DEBUG] the Backup Daemon could not be configured yet: %v", err)
+			return resource.RetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not configure the Backup Daemon on %s: %v", cfg.Machine, err)
+	}
+
+	log.Printf("[DEBUG] enabled the Backup Daemon on: %s", cfg.Machine)
+	return nil
+}
+
+// newDaemonConfig builds the admin API request which enables the Backup Daemon with the passed configuration
+func newDaemonConfig(cfg types.BackupDaemonConfig) api.DaemonConfig {
+	return api.DaemonConfig{
+		Machine: api.DaemonMachine{
+			Machine:           cfg.Machine,
+			HeadRootDirectory: cfg.HeadDirectory,
+		},
+		AssignmentEnabled:           true,
+		BackupJobsEnabled:           true,
+		Configured:                  true,
+		GarbageCollectionEnabled:    true,
+		ResourceUsageEnabled:        true,
+		RestoreQueryableJobsEnabled: true,
+		NumWorkers:                  cfg.NumWorkers,
+	}
+}
+
+// setBackupDaemonConfig stores the passed daemon configuration in the resource data
+func setBackupDaemonConfig(data *schema.ResourceData, cfg types.BackupDaemonConfig) error {
+	resourceData := make(map[string]interface{})
+	resourceData["binary"] = cfg.Binary
+	resourceData["workdir"] = cfg.WorkDir
+	resourceData["mongo_uri"] = cfg.MongoURI
+	resourceData["encryption_key"] = cfg.EncryptionKey
+	resourceData["port"] = cfg.Port
+	resourceData["central_url"] = cfg.CentralURL
+	resourceData["overrides"] = cfg.Overrides
+	resourceData["head_directory"] = cfg.HeadDirectory
+	resourceData["machine"] = cfg.Machine
+	resourceData["num_workers"] = cfg.NumWorkers
+	resourceData["retain_encryption_key"] = cfg.RetainEncryptionKey
+	resourceData["https_pem_key"] = cfg.HTTPSPEMKey
+	resourceData["https_pem_key_password"] = cfg.HTTPSPEMKeyPassword
+	resourceData["https_ca"] = cfg.HTTPSCA
+	resourceData["https_port"] = cfg.HTTPSPort
+	return data.Set("backup_daemon", []map[string]interface{}{resourceData})
+}
@@ -0,0 +1,22 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+)
+
+func TestNewDaemonConfig_unit(t *testing.T) {
+	cfg := types.BackupDaemonConfig{Machine: "backup.example.com", HeadDirectory: "/data/head", NumWorkers: 10}
+	daemonConfig := newDaemonConfig(cfg)
+
+	if daemonConfig.Machine.Machine != "backup.example.com" || daemonConfig.Machine.HeadRootDirectory != "/data/head" {
+		t.Errorf("unexpected machine: %+v", daemonConfig.Machine)
+	}
+	if daemonConfig.NumWorkers != 10 {
+		t.Errorf("unexpected number of workers: %d", daemonConfig.NumWorkers)
+	}
+	if !daemonConfig.Configured || !daemonConfig.BackupJobsEnabled || !daemonConfig.AssignmentEnabled {
+		t.Errorf("the daemon was not enabled: %+v", daemonConfig)
+	}
+}
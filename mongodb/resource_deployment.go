@@ -0,0 +1,272 @@
+package mongodb
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
+)
+
+func resourceDeployment() *schema.Resource {
+	resourceSchema := types.NewSchemaMap(types.DeploymentSchema, WithOpsManagerAPISchema)
+
+	return &schema.Resource{
+		Create: resourceMdbDeploymentCreate,
+		Read:   resourceMdbDeploymentRead,
+		Update: resourceMdbDeploymentUpdate,
+		Delete: resourceMdbDeploymentDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(util.LongCreationTimeout),
+			Read:   schema.DefaultTimeout(util.DefaultTimeout),
+			Update: schema.DefaultTimeout(util.LongCreationTimeout),
+			Delete: schema.DefaultTimeout(util.LongCreationTimeout),
+		},
+		Schema: resourceSchema,
+	}
+}
+
+// If the Create callback returns with or without an error without an ID set using SetId, the resource is assumed to not be created, and no state is saved.
+// If the Create callback returns with or without an error and an ID has been set, the resource is assumed created and all state is saved with it.
+func resourceMdbDeploymentCreate(data *schema.ResourceData, meta interface{}) error {
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	projectID := data.Get("project_id").(string)
+	processes := types.ReadDeploymentProcesses(data.Get("process").([]interface{}))
+
+	config, err := client.ReadAutomationConfig(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to read the automation config of project %s: %v", projectID, err)
+	}
+
+	// the resource is identified by its project, hence a project can only be managed by a single deployment
+	if names := definedProcessNames(config); len(names) > 0 {
+		return fmt.Errorf("project %s already defines processes %v; a project can only be managed by a single deployment", projectID, names)
+	}
+
+	applyDeployment(config, nil, processes)
+	if auth, ok := data.GetOk("auth"); ok {
+		cfg := types.ReadDeploymentAuth(auth.([]interface{}))
+		applyDeploymentAuth(config, &cfg)
+	}
+
+	if err := client.WriteAutomationConfig(projectID, config); err != nil {
+		return fmt.Errorf("failed to update the automation config of project %s: %v", projectID, err)
+	}
+	log.Printf("[DEBUG] updated the automation config of project: %s", projectID)
+	data.SetId(projectID)
+
+	if err := waitForGoalState(client, projectID, data.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	return resourceMdbDeploymentRead(data, meta)
+}
+
+// This callback should never modify the real resource.
+// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band).
+// Just like the destroy callback, the Read function should gracefully handle this case.
+func resourceMdbDeploymentRead(data *schema.ResourceData, meta interface{}) error {
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	config, err := client.ReadAutomationConfig(data.Id())
+	if api.IsNotFound(err) {
+		log.Printf("[WARN] could not find project: %s", data.Id())
+		data.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the automation config of project %s: %v", data.Id(), err)
+	}
+
+	// only read back the processes managed by this resource
+	var processes []types.DeploymentProcess
+	for _, p := range types.ReadDeploymentProcesses(data.Get("process").([]interface{})) {
+		if process, ok := readDeploymentProcess(config, p.Name); ok {
+			processes = append(processes, process)
+		}
+	}
+	if len(processes) == 0 {
+		log.Printf("[WARN] none of the deployment's processes are defined in project: %s", data.Id())
+		data.SetId("")
+		return nil
+	}
+
+	status, err := client.GetAutomationStatus(data.Id())
+	if err != nil {
+		return fmt.Errorf("failed to read the automation status of project %s: %v", data.Id(), err)
+	}
+
+	// update the resource data
+	if err := data.Set("process", types.FlattenDeploymentProcesses(processes)); err != nil {
+		return err
+	}
+	if err := data.Set("goal_version", status.GoalVersion); err != nil {
+		return err
+	}
+
+	// read back the authentication settings, to detect changes made outside of this resource
+	flattenedAuth := []interface{}{}
+	if auth, ok := readDeploymentAuth(config); ok {
+		// the default mechanism is enabled when none were specified
+		if configured, ok := data.GetOk("auth"); ok && len(types.ReadDeploymentAuth(configured.([]interface{})).Mechanisms) == 0 &&
+			len(auth.Mechanisms) == 1 && auth.Mechanisms[0] == defaultAuthMechanism {
+			auth.Mechanisms = nil
+		}
+		flattenedAuth = types.FlattenDeploymentAuth(auth)
+	}
+	if err := data.Set("auth", flattenedAuth); err != nil {
+		return err
+	}
+
+	log.Print("[DEBUG] updated the deployment resource...")
+	return nil
+}
+
+// If the Update callback returns with or without an error, the full state is saved.
+// If the ID becomes blank, the resource is destroyed (even within an update, though this shouldn't happen except in error scenarios).
+// Partial mode is a mode that can be enabled by a callback that tells Terraform that it is possible for partial state to occur.
+// When this mode is enabled, the provider must explicitly tell Terraform what is safe to persist and what is not.
+func resourceMdbDeploymentUpdate(data *schema.ResourceData, meta interface{}) error {
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	old, current := data.GetChange("process")
+	previous := types.ReadDeploymentProcesses(old.([]interface{}))
+	processes := types.ReadDeploymentProcesses(current.([]interface{}))
+
+	// shut down the processes which are no longer part of the deployment, before removing them
+	kept := make(map[string]bool)
+	for _, p := range processes {
+		kept[p.Name] = true
+	}
+	var removed []string
+	for _, p := range previous {
+		if !kept[p.Name] {
+			removed = append(removed, p.Name)
+		}
+	}
+	if len(removed) > 0 {
+		if err := shutdownProcesses(client, data.Id(), removed, data.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	config, err := client.ReadAutomationConfig(data.Id())
+	if err != nil {
+		return fmt.Errorf("failed to read the automation config of project %s: %v", data.Id(), err)
+	}
+
+	applyDeployment(config, previous, processes)
+	if data.HasChange("auth") {
+		if auth, ok := data.GetOk("auth"); ok {
+			cfg := types.ReadDeploymentAuth(auth.([]interface{}))
+			applyDeploymentAuth(config, &cfg)
+		} else {
+			applyDeploymentAuth(config, nil)
+		}
+	}
+
+	if err := client.WriteAutomationConfig(data.Id(), config); err != nil {
+		return fmt.Errorf("failed to update the automation config of project %s: %v", data.Id(), err)
+	}
+	log.Printf("[DEBUG] updated the automation config of project: %s", data.Id())
+
+	if err := waitForGoalState(client, data.Id(), data.Timeout(schema.TimeoutUpdate)); err != nil {
+		return err
+	}
+
+	return resourceMdbDeploymentRead(data, meta)
+}
+
+// If the Destroy callback returns without an error, the resource is assumed to be destroyed, and all state is removed.
+// If the Destroy callback returns with an error, the resource is assumed to still exist, and all prior state is preserved.
+// If the resource is already destroyed, this should not return an error.
+// This allows Terraform users to manually delete resources without breaking Terraform.
+func resourceMdbDeploymentDelete(data *schema.ResourceData, meta interface{}) error {
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	processes := types.ReadDeploymentProcesses(data.Get("process").([]interface{}))
+	names := make([]string, 0, len(processes))
+	for _, p := range processes {
+		names = append(names, p.Name)
+	}
+
+	// the agents must shut down the processes before they can be removed from the automation config
+	err = shutdownProcesses(client, data.Id(), names, data.Timeout(schema.TimeoutDelete))
+	if api.IsNotFound(err) {
+		data.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	config, err := client.ReadAutomationConfig(data.Id())
+	if err != nil {
+		return fmt.Errorf("failed to read the automation config of project %s: %v", data.Id(), err)
+	}
+	applyDeployment(config, processes, nil)
+	if err := client.WriteAutomationConfig(data.Id(), config); err != nil {
+		return fmt.Errorf("failed to update the automation config of project %s: %v", data.Id(), err)
+	}
+	log.Printf("[DEBUG] removed %v from the automation config of project: %s", names, data.Id())
+
+	data.SetId("")
+	return nil
+}
+
+// shutdownProcesses disables the named processes and waits for the agents to shut them down
+func shutdownProcesses(client *api.Client, projectID string, names []string, timeout time.Duration) error {
+	config, err := client.ReadAutomationConfig(projectID)
+	if err != nil {
+		return err
+	}
+
+	disableProcesses(config, names)
+	if err := client.WriteAutomationConfig(projectID, config); err != nil {
+		return fmt.Errorf("failed to disable %v in project %s: %v", names, projectID, err)
+	}
+	log.Printf("[DEBUG] disabled %v in project: %s", names, projectID)
+
+	return waitForGoalState(client, projectID, timeout)
+}
+
+// waitForGoalState waits until all the agents in the project have applied the latest automation config
+func waitForGoalState(client *api.Client, projectID string, timeout time.Duration) error {
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		status, err := client.GetAutomationStatus(projectID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		for _, process := range status.Processes {
+			if process.LastGoalVersionAchieved < status.GoalVersion {
+				return resource.RetryableError(fmt.Errorf("%s has not reached goal version %d (currently at %d)",
+					process.Name, status.GoalVersion, process.LastGoalVersionAchieved))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("the deployment in project %s did not reach goal state: %v", projectID, err)
+	}
+
+	log.Printf("[DEBUG] the deployment in project %s reached goal state", projectID)
+	return nil
+}
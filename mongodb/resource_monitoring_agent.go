@@ -0,0 +1,11 @@
+package mongodb
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+)
+
+// resourceMonitoringAgent installs the standalone monitoring agent, for Ops Manager versions which predate the unified automation agent
+func resourceMonitoringAgent() *schema.Resource {
+	return resourceAgent(types.MonitoringAgent, WithMonitoringSchema)
+}
@@ -5,16 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"math/rand"
 	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/mongodb-labs/pcgc/pkg/httpclient"
 	"github.com/mongodb-labs/pcgc/pkg/opsmanager"
 
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
 	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/ssh"
 	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
 	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
@@ -22,6 +23,9 @@ import (
 
 const commentString = "# DO NOT CHANGE - this file was generated by the MongoDB Terraform Provider"
 
+// defaultGlobalOwnerAccessList allows the global owner to call the API from any IPv4 address
+const defaultGlobalOwnerAccessList = "0.0.0.1/0"
+
 func resourceMdbOpsManager() *schema.Resource {
 	// TODO(mihaibojin): 'opsmanager' schema: move settings at the top-level
 	resourceSchema := types.NewSchemaMap(WithHostSchema, WithOpsManagerSchema)
@@ -46,13 +50,336 @@ func resourceMdbOpsManager() *schema.Resource {
 func resourceMdbOpsManagerCreate(data *schema.ResourceData, meta interface{}) error {
 	providerConfig := meta.(ProviderConfig)
 
-	host := data.Get("host").([]interface{})
-	conn := types.ReadRemoteConnection(host)
-	data.SetId(conn.ToJSON())
+	hosts := types.ReadRemoteConnections(data.Get("host").([]interface{}))
+	conn := hosts[0]
 
 	om := data.Get("opsmanager").([]interface{})
 	omConfig := types.ReadOpsManagerConfig(om)
 
+	// all application servers must be reachable through the same URL (e.g., a load balancer)
+	if len(hosts) > 1 && omConfig.CentralURL == "" {
+		return fmt.Errorf("central_url must be set when deploying Ops Manager on multiple hosts")
+	}
+	id, err := conn.ToJSON()
+	if err != nil {
+		return err
+	}
+	data.SetId(id)
+
+	// generate an encryption key, if one was not specified; all application servers share the same key
+	if omConfig.EncryptionKey == "" {
+		key, err := types.GenerateEncryptionKey()
+		if err != nil {
+			return err
+		}
+		omConfig.EncryptionKey = key
+		if err := setOpsManagerConfig(data, omConfig); err != nil {
+			return err
+		}
+	}
+
+	// compute the URL used to call the API, which other resources may reference, whether or not a global owner is registered
+	omConfig.ResolveAPIURL(conn.Hostname)
+
+	// install the application servers one at a time; the first one initializes the application database
+	// and the others join it, sharing the same encryption key
+	for _, host := range hosts {
+		if err := installOpsManager(providerConfig, host, omConfig, data.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
+	// create first user if option was passed
+	if omConfig.RegisterGlobalOwner {
+		omAPIClientNoAuth, err := api.NewClient(api.Config{BaseURL: omConfig.APIURL, CACert: omConfig.HTTPSCA})
+		if err != nil {
+			return fmt.Errorf("failed to create an Ops Manager API client: %v", err)
+		}
+
+		// wait until Ops Manager answers on the API URL, which may be served through a load balancer
+		if err := waitForOpsManagerAPI(omAPIClientNoAuth, data.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+
+		// create the first user
+		firstName, lastName, emailAddress := util.TryExtractFirstLastNameAndEmail(omConfig.GlobalOwnerUsername)
+		user := opsmanager.User{Username: omConfig.GlobalOwnerUsername, Password: omConfig.GlobalOwnerPassword, FirstName: firstName, LastName: lastName, EmailAddress: emailAddress}
+		accessList := globalOwnerAccessList(omConfig)
+		apiFirstUserResp, err := omAPIClientNoAuth.CreateFirstUser(user, accessList[0])
+		if err != nil {
+			return fmt.Errorf("failed to create first user: %v", err)
+		}
+		log.Printf("[DEBUG] Created first OM user: %s", apiFirstUserResp.User.Username)
+		omConfig.GlobalOwnerAPIKey = apiFirstUserResp.APIKey
+
+		// create the first project via the client with digestAuth, to get projectID and agentAPIKey
+		omAPIClientDigestAuth, err := api.NewClient(api.Config{BaseURL: omConfig.APIURL, Username: apiFirstUserResp.User.Username, APIKey: apiFirstUserResp.APIKey, CACert: omConfig.HTTPSCA})
+		if err != nil {
+			return fmt.Errorf("failed to create an Ops Manager API client: %v", err)
+		}
+
+		// the first user can only be created with a single access list entry; add the others
+		if len(accessList) > 1 {
+			if err := omAPIClientDigestAuth.AddUserAccessList(apiFirstUserResp.User.ID, accessList[1:]); err != nil {
+				return fmt.Errorf("failed to update the access list of the first user: %v", err)
+			}
+		}
+
+		projectName := omConfig.InitialProjectName
+		if projectName == "" {
+			projectName = resource.PrefixedUniqueId("TerraformProject-")
+		}
+		createOneProjectResp, err := omAPIClientDigestAuth.CreateOneProject(projectName, "")
+		if err != nil {
+			return fmt.Errorf("failed to create first project: %v", err)
+		}
+
+		omConfig.MMSAgentAPIKey = createOneProjectResp.AgentAPIKey
+		omConfig.MMSGroupID = createOneProjectResp.ID
+	}
+
+	// store the computed attributes
+	if err := setOpsManagerConfig(data, omConfig); err != nil {
+		return err
+	}
+
+	return resourceMdbOpsManagerRead(data, meta)
+}
+
+// This callback should never modify the real resource.
+// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band).
+// Just like the destroy callback, the Read function should gracefully handle this case.
+func resourceMdbOpsManagerRead(data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	hosts := types.ReadRemoteConnections(data.Get("host").([]interface{}))
+	conn := hosts[0]
+	omConfig := types.ReadOpsManagerConfig(data.Get("opsmanager").([]interface{}))
+
+	// a mismatched encryption key is only reported, since replacing Ops Manager would not restore the key its data was encrypted with
+	if err := checkEncryptionKey(providerConfig, hosts, omConfig); err != nil {
+		return err
+	}
+
+	// recompute the API URL, unless it was explicitly set, since central_url, the port, or the first host may have changed
+	omConfig.ResolveAPIURL(conn.Hostname)
+
+	if err := setOpsManagerConfig(data, omConfig); err != nil {
+		return err
+	}
+
+	log.Print("[DEBUG] updated the Ops Manager resource...")
+	return nil
+}
+
+// checkEncryptionKey compares the encryption key on each application server with the resource's key, logging any mismatches;
+// the keys' checksums are compared, so that the key is not transferred or logged
+func checkEncryptionKey(providerConfig ProviderConfig, hosts []types.RemoteConnection, cfg types.OpsManagerConfig) error {
+	checksum, err := types.EncryptionKeyChecksum(cfg.EncryptionKey)
+	if err != nil {
+		// keys stored before they were base64 encoded cannot be compared
+		log.Printf("[WARN] could not compare the encryption key: %v", err)
+		return nil
+	}
+
+	for _, conn := range hosts {
+		client, err := NewSSHClient(providerConfig, conn)
+		if err != nil {
+			return fmt.Errorf("could not create a SSH client: %v", err)
+		}
+
+		result := client.RunCommand(conn.SudoPrefix(fmt.Sprintf("sha256sum %s", cfg.EncryptionKeyFilename())))
+		if result.IsError() {
+			log.Printf("[WARN] could not read the encryption key on %s: %v", conn.Hostname, result)
+		} else if fields := strings.Fields(result.Stdout); len(fields) == 0 || fields[0] != checksum {
+			log.Printf("[WARN] the encryption key on %s does not match the resource's encryption key", conn.Hostname)
+		}
+	}
+	return nil
+}
+
+// If the Update callback returns with or without an error, the full state is saved.
+// If the ID becomes blank, the resource is destroyed (even within an update, though this shouldn't happen except in error scenarios).
+// Partial mode is a mode that can be enabled by a callback that tells Terraform that it is possible for partial state to occur.
+// When this mode is enabled, the provider must explicitly tell Terraform what is safe to persist and what is not.
+func resourceMdbOpsManagerUpdate(data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	old, current := data.GetChange("opsmanager")
+	oldConfig := types.ReadOpsManagerConfig(old.([]interface{}))
+	omConfig := types.ReadOpsManagerConfig(current.([]interface{}))
+
+	oldHosts, currentHosts := data.GetChange("host")
+	added, kept, removed := diffRemoteConnections(
+		types.ReadRemoteConnections(oldHosts.([]interface{})),
+		types.ReadRemoteConnections(currentHosts.([]interface{})),
+	)
+
+	if len(added)+len(kept) > 1 && omConfig.CentralURL == "" {
+		return fmt.Errorf("central_url must be set when deploying Ops Manager on multiple hosts")
+	}
+
+	// a changed api_url was explicitly set; otherwise, it is recomputed from the new configuration
+	if data.HasChange("opsmanager.0.api_url") {
+		omConfig.APIURLComputed = false
+	}
+	omConfig.ResolveAPIURL(types.ReadRemoteConnections(currentHosts.([]interface{}))[0].Hostname)
+	if err := setOpsManagerConfig(data, omConfig); err != nil {
+		return err
+	}
+
+	// join any new application servers to the existing application database
+	for _, conn := range added {
+		if err := installOpsManager(providerConfig, conn, omConfig, data.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	// only the properties files can be updated in place; all other changes are ignored or force a new resource
+	if data.HasChange("opsmanager.0.overrides") || data.HasChange("opsmanager.0.central_url") ||
+		data.HasChange("opsmanager.0.mongo_uri") || data.HasChange("opsmanager.0.port") ||
+		data.HasChange("opsmanager.0.https_pem_key") || data.HasChange("opsmanager.0.https_pem_key_password") ||
+		data.HasChange("opsmanager.0.https_ca") || data.HasChange("opsmanager.0.https_port") {
+		// reconfigure and restart one application server at a time, to keep Ops Manager available
+		for _, conn := range kept {
+			if err := reconfigureOpsManager(providerConfig, conn, omConfig, oldConfig.Overrides, data.Timeout(schema.TimeoutUpdate)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the global owner's access list is managed through the API
+	if omConfig.RegisterGlobalOwner && data.HasChange("opsmanager.0.global_owner_access_list") {
+		if err := updateGlobalOwnerAccessList(omConfig, globalOwnerAccessList(oldConfig)); err != nil {
+			return err
+		}
+	}
+
+	// stop and uninstall any application servers which were removed
+	for _, conn := range removed {
+		if err := uninstallOpsManager(providerConfig, conn, oldConfig); err != nil {
+			return err
+		}
+	}
+
+	// the resource is identified by its first application server
+	if len(removed) > 0 {
+		id, err := types.ReadRemoteConnections(currentHosts.([]interface{}))[0].ToJSON()
+		if err != nil {
+			return err
+		}
+		data.SetId(id)
+	}
+
+	return resourceMdbOpsManagerRead(data, meta)
+}
+
+// If the Destroy callback returns without an error, the resource is assumed to be destroyed, and all state is removed.
+// If the Destroy callback returns with an error, the resource is assumed to still exist, and all prior state is preserved.
+// If the resource is already destroyed, this should not return an error.
+// This allows Terraform users to manually delete resources without breaking Terraform.
+func resourceMdbOpsManagerDelete(data *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+
+	hosts := types.ReadRemoteConnections(data.Get("host").([]interface{}))
+
+	om := data.Get("opsmanager").([]interface{})
+	omConfig := types.ReadOpsManagerConfig(om)
+
+	for _, conn := range hosts {
+		if err := uninstallOpsManager(providerConfig, conn, omConfig); err != nil {
+			return err
+		}
+	}
+
+	data.SetId("")
+	return nil
+}
+
+// globalOwnerAccessList returns the CIDR blocks from which the global owner can call the API
+func globalOwnerAccessList(cfg types.OpsManagerConfig) []string {
+	if len(cfg.GlobalOwnerAccessList) == 0 {
+		return []string{defaultGlobalOwnerAccessList}
+	}
+	return cfg.GlobalOwnerAccessList
+}
+
+// updateGlobalOwnerAccessList adds any new CIDR blocks to the global owner's access list, before removing the ones
+// no longer specified, so that the global owner is never left without access
+func updateGlobalOwnerAccessList(cfg types.OpsManagerConfig, previous []string) error {
+	client, err := api.NewClient(api.Config{BaseURL: cfg.APIURL, Username: cfg.GlobalOwnerUsername, APIKey: cfg.GlobalOwnerAPIKey, CACert: cfg.HTTPSCA})
+	if err != nil {
+		return fmt.Errorf("failed to create an Ops Manager API client: %v", err)
+	}
+
+	user, err := client.GetUserByName(cfg.GlobalOwnerUsername)
+	if err != nil {
+		return fmt.Errorf("failed to find the global owner %s: %v", cfg.GlobalOwnerUsername, err)
+	}
+
+	current := globalOwnerAccessList(cfg)
+	if err := client.AddUserAccessList(user.ID, current); err != nil {
+		return fmt.Errorf("failed to update the access list of the global owner: %v", err)
+	}
+
+	kept := make(map[string]bool)
+	for _, cidrBlock := range current {
+		kept[cidrBlock] = true
+	}
+	for _, cidrBlock := range previous {
+		if kept[cidrBlock] {
+			continue
+		}
+		if err := client.DeleteUserAccessList(user.ID, cidrBlock); err != nil && !api.IsNotFound(err) {
+			return fmt.Errorf("failed to remove %s from the access list of the global owner: %v", cidrBlock, err)
+		}
+	}
+
+	log.Printf("[DEBUG] updated the access list of the global owner: %v", current)
+	return nil
+}
+
+// setOpsManagerConfig stores the passed Ops Manager configuration in the resource data
+func setOpsManagerConfig(data *schema.ResourceData, omConfig types.OpsManagerConfig) error {
+	stuff, err := json.Marshal(omConfig)
+	if err != nil {
+		return fmt.Errorf("failed to deconstruct OM schema: %v", err)
+	}
+
+	var deconstructed map[string]interface{}
+
+	if err := json.Unmarshal(stuff, &deconstructed); err != nil {
+		return fmt.Errorf("failed to reconstruct OM schema: %v", err)
+	}
+
+	var s []interface{}
+	s = append(s, deconstructed)
+
+	if err = data.Set("opsmanager", s); err != nil {
+		return fmt.Errorf("failed to replace OM config: %v", err)
+	}
+	return nil
+}
+
+// waitForOpsManagerAPI waits until Ops Manager answers requests on its API URL
+func waitForOpsManagerAPI(client *api.Client, timeout time.Duration) error {
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		if err := client.CheckHealth(); err != nil {
+			log.Printf("[DEBUG] Ops Manager is not available yet: %v", err)
+			return resource.RetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed waiting for the ops manager API to become available: %v", err)
+	}
+
+	log.Print("[DEBUG] Ops Manager is answering API requests")
+	return nil
+}
+
+// installOpsManager installs, configures, and starts Ops Manager on the specified host, waiting up to the specified timeout for it to serve requests
+func installOpsManager(providerConfig ProviderConfig, conn types.RemoteConnection, omConfig types.OpsManagerConfig, timeout time.Duration) error {
 	// create a SSH connection to the remote host
 	client, err := NewSSHClient(providerConfig, conn)
 	if err != nil {
@@ -61,222 +388,382 @@ func resourceMdbOpsManagerCreate(data *schema.ResourceData, meta interface{}) er
 
 	// create the working directory and set the appropriate permissions
 	cmd := fmt.Sprintf("bash -c \"mkdir -p %[1]s && chown $(whoami) %[1]s && chmod 0775 %[1]s\"", omConfig.WorkDir)
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(cmd)))
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not create the working directory: %v", err)
+	}
 
 	// download Ops Manager
 	localFile, err := util.DownloadFile(omConfig.Binary)
-	util.PanicOnNonNilErr(err)
+	if err != nil {
+		return fmt.Errorf("could not download %s: %v", omConfig.Binary, err)
+	}
 	defer util.LogError(localFile.Close)
 	log.Printf("[DEBUG] downloaded binary to: %s", localFile.Name())
 
 	// upload the binary
-	fileName := filepath.Base(localFile.Name())
-	remoteFilePath := path.Join(omConfig.WorkDir, fileName)
-	ssh.PanicOnError(client.UploadFile(remoteFilePath, localFile))
+	remoteFilePath := omConfig.BinaryFilename()
+	if err := client.UploadFile(remoteFilePath, localFile).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not upload the Ops Manager binary: %v", err)
+	}
 	log.Printf("[DEBUG] uploaded the binary to: %s", remoteFilePath)
 
 	// install Ops Manager
 	filetype := filepath.Ext(localFile.Name())
-	if filetype == ".tar.gz" || filetype == ".tgz" {
+	if omConfig.IsArchive() {
 		// unpack the binary
 		cmd = fmt.Sprintf("tar -C %s -xvzf %s --strip 1", omConfig.WorkDir, remoteFilePath)
-		ssh.PanicOnError(client.RunCommand(cmd))
+		if err := client.RunCommand(cmd).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not unpack the Ops Manager binary: %v", err)
+		}
 
-		// TODO(mihaibojin): support installing from tar.gz
+		// create the service user, which is otherwise created by the package
+		cmd = fmt.Sprintf("bash -c \"id -u mongodb-mms >/dev/null 2>&1 || useradd --system --user-group --no-create-home --home-dir %s --shell /bin/false mongodb-mms\"", omConfig.WorkDir)
+		if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not create the mongodb-mms service user: %v", err)
+		}
 	} else if filetype == ".deb" {
 		// install the binary
 		cmd := fmt.Sprintf(conn.SudoPrefix("dpkg -i --force-confnew %s"), remoteFilePath)
-		ssh.PanicOnError(client.RunCommand(cmd))
+		if err := client.RunCommand(cmd).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not install the Ops Manager package: %v", err)
+		}
 	} else if filetype == ".rpm" {
 		// install the binary
 		cmd := fmt.Sprintf(conn.SudoPrefix("rpm -ivh %s"), remoteFilePath)
-		ssh.PanicOnError(client.RunCommand(cmd))
+		if err := client.RunCommand(cmd).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not install the Ops Manager package: %v", err)
+		}
 	} else {
 		return fmt.Errorf("unknown file type: %v", filetype)
 	}
 	log.Print("[DEBUG] unpacked the binary on the remote host")
 
-	// configure the property overrides (conf-mms.properties)
-	err =
-		updatePropertiesFile(client, conn, omConfig.ConfigOverrideFilename(), func(props *types.PropertiesFile) {
-			props.SetPropertyValue(omConfig.GetOpsManagerTag("MongoURI"), omConfig.MongoURI)
-			props.SetComments(omConfig.GetOpsManagerTag("MongoURI"), []string{"", commentString, ""})
-
-			props.SetPropertyValue(omConfig.GetOpsManagerTag("CentralURL"), omConfig.CentralURL)
-			props.SetComments(omConfig.GetOpsManagerTag("CentralURL"), []string{"", commentString})
-			for prop, val := range omConfig.Overrides {
-				props.SetPropertyValue(prop, val.(string))
-			}
-		})
-	util.PanicOnNonNilErr(err)
+	// upload the HTTPS certificates, if specified
+	if err := uploadHTTPSCertificates(omConfig, client, conn); err != nil {
+		return err
+	}
 
-	// configure the port in mms.conf
-	err =
-		updatePropertiesFile(client, conn, omConfig.SysConfigFilename(), func(props *types.PropertiesFile) {
-			props.SetPropertyValue(omConfig.GetOpsManagerTag("Port"), strconv.Itoa(omConfig.Port))
-			props.SetComments(omConfig.GetOpsManagerTag("Port"), []string{commentString, ""})
-		})
-	util.PanicOnNonNilErr(err)
+	// configure Ops Manager's properties files
+	if err := configureOpsManager(omConfig, nil, client, conn); err != nil {
+		return err
+	}
 
 	// create the AVD, if specified as an override
-	ensureAutomationVersionsDirectory(omConfig, client, conn)
+	if err := ensureAutomationVersionsDirectory(omConfig, client, conn); err != nil {
+		return err
+	}
 
 	// upload the encryption key
-	remoteEncKeyPath := "/etc/mongodb-mms/gen.key"
-	remoteTempFile := "~/gen.key"
-	// create the encryption key's directory
-	cmd = fmt.Sprintf("mkdir -p %[1]s", filepath.Dir(remoteEncKeyPath))
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(cmd)))
-	// store the encryption key to a temp file, always ensuring no more than 24 bytes are selected
-	escaped := strings.Replace(omConfig.EncryptionKey[0:24], "'", "\\'", -1)
-	encKeyFile, err := util.ReadAllIntoTempFile(strings.NewReader(escaped), "encryption-key")
-	util.PanicOnNonNilErr(err)
-	defer util.BurnAfterReading(encKeyFile)
-	// upload the file
-	ssh.PanicOnError(client.UploadFile(remoteTempFile, encKeyFile))
-	// move the file to its final location and set the correct perms
-	cmd = fmt.Sprintf("bash -c \"mv %[1]s %[2]s; chown mongodb-mms:mongodb-mms %[2]s; chmod -R 0550 %[2]s\"", remoteTempFile, path.Base(remoteEncKeyPath))
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(cmd)))
+	encryptionKey, err := types.DecodeEncryptionKey(omConfig.EncryptionKey)
+	if err != nil {
+		return err
+	}
+	if err := uploadSecretFile(string(encryptionKey), omConfig.EncryptionKeyFilename(), "mongodb-mms", client, conn); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] uploaded the encryption key to: %s", omConfig.EncryptionKeyFilename())
 
 	// set the correct owner on all Ops Manager files
 	cmd = fmt.Sprintf("chown -R mongodb-mms:mongodb-mms %[1]s", omConfig.WorkDir)
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(cmd)))
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not change the owner of the Ops Manager files: %v", err)
+	}
+
+	// Ops Manager cannot start without its application database
+	if err := verifyApplicationDatabase(omConfig, client, conn); err != nil {
+		return err
+	}
 
 	// start the Ops Manager service
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix("/etc/init.d/mongodb-mms start")))
-	log.Printf("[DEBUG] started Ops Manager on port: %d", omConfig.Port)
+	if err := client.RunCommand(conn.SudoPrefix(omConfig.ServiceCommand("start"))).ErrorOrNil(); err != nil {
+		return withStartupLog(fmt.Errorf("could not start Ops Manager: %v", err), omConfig, client, conn)
+	}
+	log.Printf("[DEBUG] started Ops Manager on port: %d", omConfig.ServingPort())
 
-	// wait for Ops Manager to start
-	if err := ssh.WaitForOpenPort(ssh.NewOpenPortCheckerFunc(client), omConfig.Port); err != nil {
-		return fmt.Errorf("failed waiting for ops manager to start at port %d: %v", omConfig.Port, err)
+	// wait for Ops Manager to serve requests
+	if err := ssh.WaitForHTTPReady(ssh.NewHTTPCheckerFunc(client), omConfig.HealthCheckURL(), timeout); err != nil {
+		return withStartupLog(fmt.Errorf("failed waiting for ops manager to start at %s: %v", omConfig.HealthCheckURL(), err), omConfig, client, conn)
 	}
-	log.Printf("[DEBUG] confirmed connection to the Ops Manager port: %d", omConfig.Port)
+	log.Printf("[DEBUG] confirmed that Ops Manager is ready: %s", omConfig.HealthCheckURL())
+	return nil
+}
 
-	// create first user if option was passed
-	if omConfig.RegisterGlobalOwner {
-		// TODO(mihaibojin): temporary fix to address OM port != exposed port
-		apiURL := fmt.Sprintf("http://%s:%d", conn.Hostname, omConfig.ExternalPort)
-		resolver := httpclient.NewURLResolverWithPrefix(apiURL, opsmanager.PublicAPIPrefix)
-		omAPIClientNoAuth := opsmanager.NewDefaultClient(resolver)
+// reconfigureOpsManager rewrites the Ops Manager configuration on the specified host, then restarts it and waits up to the specified timeout for it to serve requests
+func reconfigureOpsManager(providerConfig ProviderConfig, conn types.RemoteConnection, omConfig types.OpsManagerConfig, previousOverrides map[string]interface{}, timeout time.Duration) error {
+	// create a SSH connection to the remote host
+	client, err := NewSSHClient(providerConfig, conn)
+	if err != nil {
+		return fmt.Errorf("could not create a SSH client: %v", err)
+	}
 
-		// create the first user
-		firstName, lastName, emailAddress := util.TryExtractFirstLastNameAndEmail(omConfig.GlobalOwnerUsername)
-		user := opsmanager.User{Username: omConfig.GlobalOwnerUsername, Password: omConfig.GlobalOwnerPassword, FirstName: firstName, LastName: lastName, EmailAddress: emailAddress}
-		apiFirstUserResp, err := omAPIClientNoAuth.CreateFirstUser(user, "0.0.0.1/0")
-		if err != nil {
-			return fmt.Errorf("failed to create first user: %v", err)
-		}
-		log.Printf("[DEBUG] Created first OM user: %s", apiFirstUserResp.User.Username)
+	// rewrite Ops Manager's properties files
+	if err := uploadHTTPSCertificates(omConfig, client, conn); err != nil {
+		return err
+	}
+	if err := configureOpsManager(omConfig, previousOverrides, client, conn); err != nil {
+		return err
+	}
+	if err := ensureAutomationVersionsDirectory(omConfig, client, conn); err != nil {
+		return err
+	}
 
-		// create the first project via the client with digestAuth, to get projectID and agentAPIKey
-		omAPIClientDigestAuth := opsmanager.NewClientWithDigestAuth(resolver, apiFirstUserResp.User.Username, apiFirstUserResp.APIKey)
-		projectName := fmt.Sprintf("TerraformProject-%d", rand.Intn(10000000))
-		createOneProjectResp, err := omAPIClientDigestAuth.CreateOneProject(projectName, "")
-		if err != nil {
-			return fmt.Errorf("failed to create first project: %v", err)
-		}
+	// Ops Manager cannot start without its application database
+	if err := verifyApplicationDatabase(omConfig, client, conn); err != nil {
+		return err
+	}
 
-		omConfig.MMSAgentAPIKey = createOneProjectResp.AgentAPIKey
-		omConfig.MMSGroupID = createOneProjectResp.ID
+	// restart Ops Manager, to pick up the new configuration
+	if err := client.RunCommand(conn.SudoPrefix(omConfig.ServiceCommand("restart"))).ErrorOrNil(); err != nil {
+		return withStartupLog(fmt.Errorf("could not restart Ops Manager: %v", err), omConfig, client, conn)
+	}
+	log.Printf("[DEBUG] restarted Ops Manager on port: %d", omConfig.ServingPort())
 
-		stuff, err := json.Marshal(omConfig)
-		if err != nil {
-			return fmt.Errorf("failed to deconstruct OM schema: %v", err)
-		}
+	// wait for Ops Manager to serve requests
+	if err := ssh.WaitForHTTPReady(ssh.NewHTTPCheckerFunc(client), omConfig.HealthCheckURL(), timeout); err != nil {
+		return withStartupLog(fmt.Errorf("failed waiting for ops manager to restart at %s: %v", omConfig.HealthCheckURL(), err), omConfig, client, conn)
+	}
+	log.Printf("[DEBUG] confirmed that Ops Manager is ready: %s", omConfig.HealthCheckURL())
 
-		var deconstructed map[string]interface{}
+	return nil
+}
 
-		if err := json.Unmarshal(stuff, &deconstructed); err != nil {
-			return fmt.Errorf("failed to reconstruct OM schema: %v", err)
-		}
+// uninstallOpsManager stops and uninstalls Ops Manager from the specified host
+func uninstallOpsManager(providerConfig ProviderConfig, conn types.RemoteConnection, omConfig types.OpsManagerConfig) error {
+	// create a SSH connection to the remote host
+	client, err := NewSSHClient(providerConfig, conn)
+	if err != nil {
+		return fmt.Errorf("could not create a SSH client: %v", err)
+	}
 
-		var s []interface{}
-		s = append(s, deconstructed)
+	// stop the Ops Manager service; do not fail if it was already stopped or removed
+	result := client.RunCommand(conn.SudoPrefix(omConfig.ServiceCommand("stop")))
+	if result.IsError() {
+		log.Printf("[WARN] could not stop Ops Manager, it may have already been stopped: %v", result)
+	}
+	log.Printf("[DEBUG] stopped Ops Manager on: %s", conn.Hostname)
 
-		if err = data.Set("opsmanager", s); err != nil {
-			return fmt.Errorf("failed to replace OM config: %v", err)
+	// uninstall the Ops Manager package
+	if cmd, ok := omConfig.UninstallCommand(); ok {
+		if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not uninstall the Ops Manager package: %v", err)
 		}
+		log.Print("[DEBUG] uninstalled the Ops Manager package")
 	}
 
-	return resourceMdbOpsManagerRead(data, meta)
-}
+	// remove the files installed by Ops Manager, keeping the working directory, which may be shared with other processes
+	if err := client.RunCommand(conn.SudoPrefix(omConfig.RemoveFilesCommand())).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not remove the Ops Manager files: %v", err)
+	}
+	cmd := fmt.Sprintf("rm -f %s %s", omConfig.HTTPSPEMKeyFilename(), omConfig.HTTPSCAFilename())
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not remove the HTTPS certificates: %v", err)
+	}
+	log.Printf("[DEBUG] removed the Ops Manager files from: %s", omConfig.WorkDir)
 
-// This callback should never modify the real resource.
-// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band).
-// Just like the destroy callback, the Read function should gracefully handle this case.
-func resourceMdbOpsManagerRead(data *schema.ResourceData, meta interface{}) error {
-	// https://www.terraform.io/docs/extend/writing-custom-providers.html#implementing-a-more-complex-read
+	// losing the encryption key makes the application database unreadable; only remove it if requested
+	if !omConfig.RetainEncryptionKey {
+		cmd = fmt.Sprintf("rm -f %s", omConfig.EncryptionKeyFilename())
+		if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not remove the encryption key: %v", err)
+		}
+		log.Printf("[DEBUG] removed the encryption key: %s", omConfig.EncryptionKeyFilename())
+	}
 
-	// TODO(mihaibojin): set the correct id
+	return nil
+}
 
-	// ssh connect
-	// attempt to connect to MongoDB via port
-	ok := true
+// diffRemoteConnections compares two lists of hosts and returns the hosts which were added, kept, and removed;
+// hosts are compared by their address, ignoring any changes to their credentials
+func diffRemoteConnections(old []types.RemoteConnection, current []types.RemoteConnection) (added []types.RemoteConnection, kept []types.RemoteConnection, removed []types.RemoteConnection) {
+	hostKey := func(conn types.RemoteConnection) types.RemoteConnection {
+		conn.Password, conn.PrivateKey, conn.HostKey = "", "", ""
+		return conn
+	}
 
-	// If the resource does not exist, inform Terraform. We want to immediately
-	// return here to prevent further processing.
-	if !ok {
-		log.Printf("[WARN] could not connect: %s", data.Id())
-		return nil
+	oldHosts := make(map[types.RemoteConnection]bool)
+	for _, conn := range old {
+		oldHosts[hostKey(conn)] = true
 	}
 
-	log.Print("[DEBUG] updated the Ops Manager resource...")
-	return nil
-}
+	currentHosts := make(map[types.RemoteConnection]bool)
+	for _, conn := range current {
+		currentHosts[hostKey(conn)] = true
+		if oldHosts[hostKey(conn)] {
+			kept = append(kept, conn)
+		} else {
+			added = append(added, conn)
+		}
+	}
 
-// If the Update callback returns with or without an error, the full state is saved.
-// If the ID becomes blank, the resource is destroyed (even within an update, though this shouldn't happen except in error scenarios).
-// Partial mode is a mode that can be enabled by a callback that tells Terraform that it is possible for partial state to occur.
-// When this mode is enabled, the provider must explicitly tell Terraform what is safe to persist and what is not.
-func resourceMdbOpsManagerUpdate(data *schema.ResourceData, meta interface{}) error {
-	return resourceMdbProcessRead(data, meta)
-}
+	for _, conn := range old {
+		if !currentHosts[hostKey(conn)] {
+			removed = append(removed, conn)
+		}
+	}
 
-// If the Destroy callback returns without an error, the resource is assumed to be destroyed, and all state is removed.
-// If the Destroy callback returns with an error, the resource is assumed to still exist, and all prior state is preserved.
-// If the resource is already destroyed, this should not return an error.
-// This allows Terraform users to manually delete resources without breaking Terraform.
-func resourceMdbOpsManagerDelete(data *schema.ResourceData, meta interface{}) error {
-	return nil
+	return
 }
 
 // updatePropertiesFile updates a remote property file, given a set of modifications defined in updateProps
 func updatePropertiesFile(client *ssh.Client, conn types.RemoteConnection, remoteFile string, updateProps func(*types.PropertiesFile)) error {
 	// back up the old file
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(fmt.Sprintf("cp %s %s.backup", remoteFile, remoteFile))))
+	if err := client.RunCommand(conn.SudoPrefix(fmt.Sprintf("cp %s %s.backup", remoteFile, remoteFile))).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not back up %s: %v", remoteFile, err)
+	}
 	log.Printf("[DEBUG] backed up: %s", remoteFile)
 
 	// download the configuration file
 	result := client.RunCommand(conn.SudoPrefix(fmt.Sprintf("cat %s", remoteFile)))
-	ssh.PanicOnError(result)
+	if result.IsError() {
+		return fmt.Errorf("could not read %s: %v", remoteFile, result)
+	}
 	log.Printf("[DEBUG] downloaded the file from: %s", remoteFile)
 
 	// parse the configuration into a struct and apply the updates
-	config := types.NewPropertiesFile(result.Stdout)
+	config, err := types.NewPropertiesFile(result.Stdout)
+	if err != nil {
+		return fmt.Errorf("could not parse %s on %s: %v", remoteFile, conn.Hostname, err)
+	}
 	updateProps(config)
 	configData, err := config.Write()
-	util.PanicOnNonNilErr(err)
+	if err != nil {
+		return fmt.Errorf("could not write %s: %v", remoteFile, err)
+	}
 
 	// temporarily set file permissions to 0777
 	cmd := fmt.Sprintf("chmod 0777 %[1]s", remoteFile)
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(cmd)))
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not change the permissions of %s: %v", remoteFile, err)
+	}
 
 	// upload the config file to the remote host
-	ssh.PanicOnError(client.UploadData(remoteFile, bufio.NewReader(strings.NewReader(configData))))
+	if err := client.UploadData(remoteFile, bufio.NewReader(strings.NewReader(configData))).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not upload %s: %v", remoteFile, err)
+	}
 	log.Printf("[DEBUG] uploaded the config file to the remote host, at: %s", remoteFile)
 
 	// revert permissions to 0755
 	cmd = fmt.Sprintf("chmod 0755 %[1]s", remoteFile)
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(cmd)))
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not change the permissions of %s: %v", remoteFile, err)
+	}
+
+	return nil
+}
+
+// configureOpsManager writes the Ops Manager configuration into its properties files (conf-mms.properties and mms.conf)
+// any keys found in previousOverrides, but not in the current overrides, are removed from the configuration
+func configureOpsManager(cfg types.OpsManagerConfig, previousOverrides map[string]interface{}, client *ssh.Client, conn types.RemoteConnection) error {
+	// configure the property overrides (conf-mms.properties)
+	err :=
+		updatePropertiesFile(client, conn, cfg.ConfigOverrideFilename(), func(props *types.PropertiesFile) {
+			props.SetPropertyValue(cfg.GetOpsManagerTag("MongoURI"), cfg.MongoURI)
+			props.SetComments(cfg.GetOpsManagerTag("MongoURI"), []string{"", commentString, ""})
+
+			props.SetPropertyValue(cfg.GetOpsManagerTag("CentralURL"), cfg.CentralURL)
+			props.SetComments(cfg.GetOpsManagerTag("CentralURL"), []string{"", commentString})
+
+			// serve HTTPS, if a certificate was specified; otherwise, remove any previous HTTPS settings
+			setOptionalProperty(props, cfg.GetOpsManagerTag("HTTPSPEMKey"), cfg.HTTPSPEMKeyFilename(), cfg.IsHTTPS())
+			setOptionalProperty(props, cfg.GetOpsManagerTag("HTTPSPEMKeyPassword"), cfg.HTTPSPEMKeyPassword, cfg.IsHTTPS() && cfg.HTTPSPEMKeyPassword != "")
+			setOptionalProperty(props, cfg.GetOpsManagerTag("HTTPSCA"), cfg.HTTPSCAFilename(), cfg.IsHTTPS() && cfg.HTTPSCA != "")
+
+			for prop := range previousOverrides {
+				if _, ok := cfg.Overrides[prop]; !ok {
+					props.RemoveProperty(prop)
+				}
+			}
+			for prop, val := range cfg.Overrides {
+				props.SetPropertyValue(prop, val.(string))
+			}
+		})
+	if err != nil {
+		return fmt.Errorf("could not configure Ops Manager: %v", err)
+	}
+
+	// configure the port in mms.conf
+	err =
+		updatePropertiesFile(client, conn, cfg.SysConfigFilename(), func(props *types.PropertiesFile) {
+			props.SetPropertyValue(cfg.GetOpsManagerTag("Port"), strconv.Itoa(cfg.Port))
+			props.SetComments(cfg.GetOpsManagerTag("Port"), []string{commentString, ""})
+			if cfg.IsHTTPS() {
+				props.SetPropertyValue(cfg.GetOpsManagerTag("HTTPSPort"), strconv.Itoa(cfg.HTTPSPort))
+			}
 
+			// archive installs do not ship a mms.conf pointing to the service user and encryption key
+			if cfg.IsArchive() {
+				props.SetPropertyValue("MMS_USER", "mongodb-mms")
+				props.SetPropertyValue("ENC_KEY_PATH", cfg.EncryptionKeyFilename())
+			}
+		})
+	if err != nil {
+		return fmt.Errorf("could not configure Ops Manager: %v", err)
+	}
 	return nil
 }
 
+// setOptionalProperty sets the specified property if enabled, or removes it otherwise
+func setOptionalProperty(props *types.PropertiesFile, key string, value string, enabled bool) {
+	if enabled {
+		props.SetPropertyValue(key, value)
+	} else {
+		props.RemoveProperty(key)
+	}
+}
+
 // ensureAutomationVersionsDirectory creates the automation versions directory if specified as an override
-func ensureAutomationVersionsDirectory(cfg types.OpsManagerConfig, client *ssh.Client, conn types.RemoteConnection) {
+func ensureAutomationVersionsDirectory(cfg types.OpsManagerConfig, client *ssh.Client, conn types.RemoteConnection) error {
 	if avd, ok := cfg.Overrides["automation.versions.directory"]; ok {
 		// create the automation directory
 		cmd := fmt.Sprintf("bash -c \"mkdir -p %[1]s && chown mongodb-mms:mongodb-mms %[1]s && chmod 0775 %[1]s\"", avd)
-		ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(cmd)))
+		if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+			return fmt.Errorf("could not create the automation versions directory: %v", err)
+		}
 	}
+	return nil
+}
+
+// uploadHTTPSCertificates uploads the certificates used by Ops Manager to serve HTTPS, if specified
+func uploadHTTPSCertificates(cfg types.OpsManagerConfig, client *ssh.Client, conn types.RemoteConnection) error {
+	if !cfg.IsHTTPS() {
+		return nil
+	}
+
+	if err := uploadSecretFile(cfg.HTTPSPEMKey, cfg.HTTPSPEMKeyFilename(), "mongodb-mms", client, conn); err != nil {
+		return err
+	}
+	if cfg.HTTPSCA != "" {
+		if err := uploadSecretFile(cfg.HTTPSCA, cfg.HTTPSCAFilename(), "mongodb-mms", client, conn); err != nil {
+			return err
+		}
+	}
+	log.Printf("[DEBUG] uploaded the HTTPS certificates to: %s", filepath.Dir(cfg.HTTPSPEMKeyFilename()))
+	return nil
+}
+
+// uploadSecretFile uploads the passed contents to remotePath, only allowing the specified owner (e.g. a service user) to read it
+func uploadSecretFile(contents string, remotePath string, owner string, client *ssh.Client, conn types.RemoteConnection) error {
+	// create the destination directory
+	cmd := fmt.Sprintf("mkdir -p %s", filepath.Dir(remotePath))
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not create the directory for %s: %v", remotePath, err)
+	}
+
+	// store the contents in a temp file, which is removed once uploaded
+	localFile, err := util.ReadAllIntoTempFile(strings.NewReader(contents), path.Base(remotePath))
+	if err != nil {
+		return fmt.Errorf("could not prepare %s for upload: %v", remotePath, err)
+	}
+	defer util.BurnAfterReading(localFile)
+
+	// upload the file to a temporary location, then move it into place and restrict its permissions
+	remoteTempFile := path.Join("/tmp", filepath.Base(localFile.Name()))
+	if err := client.UploadFile(remoteTempFile, localFile).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not upload %s: %v", remotePath, err)
+	}
+	cmd = fmt.Sprintf("bash -c \"mv %[1]s %[2]s && chown %[3]s:%[3]s %[2]s && chmod 0400 %[2]s\"", remoteTempFile, remotePath, owner)
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not move %s into place: %v", remotePath, err)
+	}
+	return nil
 }
@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
+)
+
+func resourceOrganization() *schema.Resource {
+	resourceSchema := types.NewSchemaMap(types.OrganizationSchema, WithOpsManagerAPISchema)
+
+	return &schema.Resource{
+		Create: resourceMdbOrganizationCreate,
+		Read:   resourceMdbOrganizationRead,
+		Update: resourceMdbOrganizationUpdate,
+		Delete: resourceMdbOrganizationDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(util.DefaultTimeout),
+			Read:   schema.DefaultTimeout(util.DefaultTimeout),
+			Update: schema.DefaultTimeout(util.DefaultTimeout),
+			Delete: schema.DefaultTimeout(util.DefaultTimeout),
+		},
+		Schema: resourceSchema,
+	}
+}
+
+// If the Create callback returns with or without an error without an ID set using SetId, the resource is assumed to not be created, and no state is saved.
+// If the Create callback returns with or without an error and an ID has been set, the resource is assumed created and all state is saved with it.
+func resourceMdbOrganizationCreate(data *schema.ResourceData, meta interface{}) error {
+	name := data.Get("name").(string)
+
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	org, err := client.CreateOrganization(name)
+	if err != nil {
+		return fmt.Errorf("failed to create organization %s: %v", name, err)
+	}
+	log.Printf("[DEBUG] created organization %s (%s)", org.Name, org.ID)
+	data.SetId(org.ID)
+
+	return resourceMdbOrganizationRead(data, meta)
+}
+
+// This callback should never modify the real resource.
+// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band).
+// Just like the destroy callback, the Read function should gracefully handle this case.
+func resourceMdbOrganizationRead(data *schema.ResourceData, meta interface{}) error {
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	org, err := client.GetOrganization(data.Id())
+	if api.IsNotFound(err) {
+		log.Printf("[WARN] could not find organization: %s", data.Id())
+		data.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read organization %s: %v", data.Id(), err)
+	}
+
+	// update the resource data
+	if err := data.Set("name", org.Name); err != nil {
+		return err
+	}
+	if err := data.Set("org_id", org.ID); err != nil {
+		return err
+	}
+
+	log.Print("[DEBUG] updated the organization resource...")
+	return nil
+}
+
+// If the Update callback returns with or without an error, the full state is saved.
+// If the ID becomes blank, the resource is destroyed (even within an update, though this shouldn't happen except in error scenarios).
+// Partial mode is a mode that can be enabled by a callback that tells Terraform that it is possible for partial state to occur.
+// When this mode is enabled, the provider must explicitly tell Terraform what is safe to persist and what is not.
+func resourceMdbOrganizationUpdate(data *schema.ResourceData, meta interface{}) error {
+	if data.HasChange("name") {
+		name := data.Get("name").(string)
+		client, err := NewOpsManagerAPIClient(data, meta)
+		if err != nil {
+			return err
+		}
+
+		if _, err := client.RenameOrganization(data.Id(), name); err != nil {
+			return fmt.Errorf("failed to rename organization %s to %s: %v", data.Id(), name, err)
+		}
+		log.Printf("[DEBUG] renamed organization %s to: %s", data.Id(), name)
+	}
+
+	return resourceMdbOrganizationRead(data, meta)
+}
+
+// If the Destroy callback returns without an error, the resource is assumed to be destroyed, and all state is removed.
+// If the Destroy callback returns with an error, the resource is assumed to still exist, and all prior state is preserved.
+// If the resource is already destroyed, this should not return an error.
+// This allows Terraform users to manually delete resources without breaking Terraform.
+func resourceMdbOrganizationDelete(data *schema.ResourceData, meta interface{}) error {
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteOrganization(data.Id())
+	if err != nil && !api.IsNotFound(err) {
+		return fmt.Errorf("failed to delete organization %s: %v", data.Id(), err)
+	}
+
+	data.SetId("")
+	return nil
+}
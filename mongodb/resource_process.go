@@ -36,14 +36,21 @@ func resourceMdbProcess() *schema.Resource {
 	}
 }
 
-// NewSSHClient build a new SSH client using the provided parameters
+// NewSSHClient build a new SSH client using the provided parameters, or reuse the provider's connection to the same host
 func NewSSHClient(pc ProviderConfig, rc types.RemoteConnection) (*ssh.Client, error) {
-	return ssh.NewClient(
+	params := []func(*ssh.Connection) error{
 		ssh.WithHostParams(rc.User, rc.Hostname, rc.Port),
 		WithProviderConfig(&pc),
+		ssh.WithPassword(rc.Password),
 		ssh.WithPrivateKey(rc.PrivateKey),
 		ssh.WithHostKey(rc.HostKey),
-	)
+	}
+
+	// reuse the connection to the host, if the provider shares one
+	if pc.SSHPool != nil {
+		return pc.SSHPool.Get(params...)
+	}
+	return ssh.NewClient(params...)
 }
 
 // If the Create callback returns with or without an error without an ID set using SetId, the resource is assumed to not be created, and no state is saved.
@@ -54,7 +61,11 @@ func resourceMdbProcessCreate(data *schema.ResourceData, meta interface{}) error
 	// read host params
 	host := data.Get("host").([]interface{})
 	conn := types.ReadRemoteConnection(host)
-	data.SetId(conn.ToJSON())
+	id, err := conn.ToJSON()
+	if err != nil {
+		return err
+	}
+	data.SetId(id)
 
 	// read process config
 	process := data.Get("mongod").([]interface{})
@@ -70,7 +81,9 @@ func resourceMdbProcessCreate(data *schema.ResourceData, meta interface{}) error
 	dbPath := filepath.Join(dbConfig.WorkDir, dbConfig.DbPath)
 	logPath := filepath.Join(dbConfig.WorkDir, dbConfig.DbPath, dbConfig.LogPath)
 	cmd := fmt.Sprintf("bash -c \"mkdir -p %[1]s %[2]s %[3]s && chown $(whoami) %[1]s %[2]s %[3]s && chmod 0775 %[1]s %[2]s %[3]s\"", dbConfig.WorkDir, dbPath, filepath.Dir(logPath))
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(cmd)))
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not create the working directories: %v", err)
+	}
 
 	// create a MongoDB configuration file
 	cfg := config.NewMongoDBConfig()
@@ -88,36 +101,50 @@ func resourceMdbProcessCreate(data *schema.ResourceData, meta interface{}) error
 	cfg.SystemLog.Path = logPath
 	cfg.SystemLog.Destination = "file"
 	cfgFile, err := cfg.SaveToTempFile("")
-	util.PanicOnNonNilErr(err)
+	if err != nil {
+		return fmt.Errorf("could not save the MongoDB configuration: %v", err)
+	}
 	defer util.BurnAfterReading(cfgFile)
 
 	// upload the config file to the remote host
 	remoteConfigPath := dbConfig.ConfigFilename()
-	ssh.PanicOnError(client.UploadFile(remoteConfigPath, cfgFile))
+	if err := client.UploadFile(remoteConfigPath, cfgFile).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not upload the MongoDB configuration: %v", err)
+	}
 
 	// download the MongoDB binary on the local host
 	localFile, err := util.DownloadFile(dbConfig.Binary)
-	util.PanicOnNonNilErr(err)
+	if err != nil {
+		return fmt.Errorf("could not download %s: %v", dbConfig.Binary, err)
+	}
 	defer util.LogError(localFile.Close)
 	log.Printf("[DEBUG] downloaded binary to: %s", localFile.Name())
 
 	// upload the binary
 	remoteFilePath := path.Join(dbConfig.WorkDir, filepath.Base(localFile.Name()))
-	ssh.PanicOnError(client.UploadFile(remoteFilePath, localFile))
+	if err := client.UploadFile(remoteFilePath, localFile).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not upload the MongoDB binary: %v", err)
+	}
 
 	// unpack the binary
 	cmd = fmt.Sprintf("tar -C %s -xvzf %s --strip 1", dbConfig.WorkDir, remoteFilePath)
-	ssh.PanicOnError(client.RunCommand(cmd))
+	if err := client.RunCommand(cmd).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not unpack the MongoDB binary: %v", err)
+	}
 	log.Printf("[DEBUG] unpacked the binary in: %s", dbConfig.WorkDir)
 
 	// start the process
 	cmd = fmt.Sprintf("%s/bin/mongod -f %s || cat %s", dbConfig.WorkDir, remoteConfigPath, logPath)
-	ssh.PanicOnError(client.RunCommand(conn.SudoPrefix(cmd)))
+	if err := client.RunCommand(conn.SudoPrefix(cmd)).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not start MongoDB: %v", err)
+	}
 	log.Print("[DEBUG] started MongoD...")
 
 	// check the connection
 	cmd = fmt.Sprintf("%s/bin/mongo --quiet --port %d --eval 'quit()'", dbConfig.WorkDir, dbConfig.Port)
-	ssh.PanicOnError(client.RunCommand(cmd))
+	if err := client.RunCommand(cmd).ErrorOrNil(); err != nil {
+		return fmt.Errorf("could not connect to MongoDB: %v", err)
+	}
 	log.Printf("[DEBUG] Successfully connected to MongoDB on port %d", dbConfig.Port)
 
 	return resourceMdbProcessRead(data, meta)
@@ -148,7 +175,9 @@ func resourceMdbProcessRead(data *schema.ResourceData, meta interface{}) error {
 
 	// load the configuration file
 	result := client.RunCommand(fmt.Sprintf("cat %s", currentConfig.ConfigFilename()))
-	ssh.PanicOnError(result)
+	if result.IsError() {
+		return fmt.Errorf("could not read the MongoDB configuration: %v", result)
+	}
 
 	// parse the configuration into a config.MongoDB struct
 	mongoDBConfig, err := config.LoadFromString(result.Stdout)
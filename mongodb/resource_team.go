@@ -0,0 +1,178 @@
+package mongodb
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
+)
+
+func resourceTeam() *schema.Resource {
+	resourceSchema := types.NewSchemaMap(types.TeamSchema, WithOpsManagerAPISchema)
+
+	return &schema.Resource{
+		Create: resourceMdbTeamCreate,
+		Read:   resourceMdbTeamRead,
+		Update: resourceMdbTeamUpdate,
+		Delete: resourceMdbTeamDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(util.DefaultTimeout),
+			Read:   schema.DefaultTimeout(util.DefaultTimeout),
+			Update: schema.DefaultTimeout(util.DefaultTimeout),
+			Delete: schema.DefaultTimeout(util.DefaultTimeout),
+		},
+		Schema: resourceSchema,
+	}
+}
+
+// If the Create callback returns with or without an error without an ID set using SetId, the resource is assumed to not be created, and no state is saved.
+// If the Create callback returns with or without an error and an ID has been set, the resource is assumed created and all state is saved with it.
+func resourceMdbTeamCreate(data *schema.ResourceData, meta interface{}) error {
+	orgID := data.Get("org_id").(string)
+	name := data.Get("name").(string)
+	usernames, _ := types.ReadStringList(map[string]interface{}{"usernames": data.Get("usernames")}, "usernames")
+
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	team, err := client.CreateTeam(orgID, name, usernames)
+	if err != nil {
+		return fmt.Errorf("failed to create team %s: %v", name, err)
+	}
+	log.Printf("[DEBUG] created team %s (%s)", team.Name, team.ID)
+	data.SetId(team.ID)
+
+	return resourceMdbTeamRead(data, meta)
+}
+
+// This callback should never modify the real resource.
+// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band).
+// Just like the destroy callback, the Read function should gracefully handle this case.
+func resourceMdbTeamRead(data *schema.ResourceData, meta interface{}) error {
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+	orgID := data.Get("org_id").(string)
+
+	team, err := client.GetTeam(orgID, data.Id())
+	if api.IsNotFound(err) {
+		log.Printf("[WARN] could not find team: %s", data.Id())
+		data.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read team %s: %v", data.Id(), err)
+	}
+
+	users, err := client.GetTeamUsers(orgID, data.Id())
+	if err != nil {
+		return fmt.Errorf("failed to read the members of team %s: %v", data.Id(), err)
+	}
+	usernames := make([]interface{}, 0, len(users))
+	for _, user := range users {
+		usernames = append(usernames, user.Username)
+	}
+
+	// update the resource data
+	if err := data.Set("name", team.Name); err != nil {
+		return err
+	}
+	if err := data.Set("usernames", usernames); err != nil {
+		return err
+	}
+	if err := data.Set("team_id", team.ID); err != nil {
+		return err
+	}
+
+	log.Print("[DEBUG] updated the team resource...")
+	return nil
+}
+
+// If the Update callback returns with or without an error, the full state is saved.
+// If the ID becomes blank, the resource is destroyed (even within an update, though this shouldn't happen except in error scenarios).
+// Partial mode is a mode that can be enabled by a callback that tells Terraform that it is possible for partial state to occur.
+// When this mode is enabled, the provider must explicitly tell Terraform what is safe to persist and what is not.
+func resourceMdbTeamUpdate(data *schema.ResourceData, meta interface{}) error {
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+	orgID := data.Get("org_id").(string)
+
+	if data.HasChange("name") {
+		name := data.Get("name").(string)
+		if _, err := client.RenameTeam(orgID, data.Id(), name); err != nil {
+			return fmt.Errorf("failed to rename team %s to %s: %v", data.Id(), name, err)
+		}
+		log.Printf("[DEBUG] renamed team %s to: %s", data.Id(), name)
+	}
+
+	if data.HasChange("usernames") {
+		old, current := data.GetChange("usernames")
+		changes := map[string]interface{}{
+			"added":   current.(*schema.Set).Difference(old.(*schema.Set)),
+			"removed": old.(*schema.Set).Difference(current.(*schema.Set)),
+		}
+		added, _ := types.ReadStringList(changes, "added")
+		removed, _ := types.ReadStringList(changes, "removed")
+
+		// the API identifies team members by their user ID
+		var addedIDs []string
+		for _, username := range added {
+			user, err := client.GetUserByName(username)
+			if err != nil {
+				return fmt.Errorf("failed to find user %s: %v", username, err)
+			}
+			addedIDs = append(addedIDs, user.ID)
+		}
+		if len(addedIDs) > 0 {
+			if err := client.AddTeamUsers(orgID, data.Id(), addedIDs); err != nil {
+				return fmt.Errorf("failed to add %v to team %s: %v", added, data.Id(), err)
+			}
+		}
+
+		for _, username := range removed {
+			user, err := client.GetUserByName(username)
+			if api.IsNotFound(err) {
+				// a deleted user is no longer a member of any team
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to find user %s: %v", username, err)
+			}
+			if err := client.RemoveTeamUser(orgID, data.Id(), user.ID); err != nil && !api.IsNotFound(err) {
+				return fmt.Errorf("failed to remove %s from team %s: %v", username, data.Id(), err)
+			}
+		}
+		log.Printf("[DEBUG] updated the members of team %s; added: %v, removed: %v", data.Id(), added, removed)
+	}
+
+	return resourceMdbTeamRead(data, meta)
+}
+
+// If the Destroy callback returns without an error, the resource is assumed to be destroyed, and all state is removed.
+// If the Destroy callback returns with an error, the resource is assumed to still exist, and all prior state is preserved.
+// If the resource is already destroyed, this should not return an error.
+// This allows Terraform users to manually delete resources without breaking Terraform.
+func resourceMdbTeamDelete(data *schema.ResourceData, meta interface{}) error {
+	orgID := data.Get("org_id").(string)
+
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteTeam(orgID, data.Id())
+	if err != nil && !api.IsNotFound(err) {
+		return fmt.Errorf("failed to delete team %s: %v", data.Id(), err)
+	}
+
+	data.SetId("")
+	return nil
+}
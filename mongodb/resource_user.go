@@ -0,0 +1,168 @@
+package mongodb
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/pcgc/pkg/opsmanager"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/api"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/types"
+	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
+)
+
+func resourceUser() *schema.Resource {
+	resourceSchema := types.NewSchemaMap(types.UserSchema, WithOpsManagerAPISchema)
+
+	return &schema.Resource{
+		Create: resourceMdbUserCreate,
+		Read:   resourceMdbUserRead,
+		Update: resourceMdbUserUpdate,
+		Delete: resourceMdbUserDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(util.DefaultTimeout),
+			Read:   schema.DefaultTimeout(util.DefaultTimeout),
+			Update: schema.DefaultTimeout(util.DefaultTimeout),
+			Delete: schema.DefaultTimeout(util.DefaultTimeout),
+		},
+		Schema: resourceSchema,
+	}
+}
+
+// If the Create callback returns with or without an error without an ID set using SetId, the resource is assumed to not be created, and no state is saved.
+// If the Create callback returns with or without an error and an ID has been set, the resource is assumed created and all state is saved with it.
+func resourceMdbUserCreate(data *schema.ResourceData, meta interface{}) error {
+	user := readUserRequest(data)
+
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	result, err := client.CreateUser(user)
+	if err != nil {
+		return fmt.Errorf("failed to create user %s: %v", user.Username, err)
+	}
+	log.Printf("[DEBUG] created user %s (%s)", result.Username, result.ID)
+	data.SetId(result.ID)
+
+	return resourceMdbUserRead(data, meta)
+}
+
+// This callback should never modify the real resource.
+// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band).
+// Just like the destroy callback, the Read function should gracefully handle this case.
+func resourceMdbUserRead(data *schema.ResourceData, meta interface{}) error {
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	user, err := client.GetUser(data.Id())
+	if api.IsNotFound(err) {
+		log.Printf("[WARN] could not find user: %s", data.Id())
+		data.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read user %s: %v", data.Id(), err)
+	}
+
+	// update the resource data; the password cannot be read back
+	if err := data.Set("username", user.Username); err != nil {
+		return err
+	}
+	if err := data.Set("first_name", user.FirstName); err != nil {
+		return err
+	}
+	if err := data.Set("last_name", user.LastName); err != nil {
+		return err
+	}
+	if err := data.Set("email_address", user.EmailAddress); err != nil {
+		return err
+	}
+	if err := data.Set("role", types.FlattenUserRoles(user.Roles)); err != nil {
+		return err
+	}
+	if err := data.Set("user_id", user.ID); err != nil {
+		return err
+	}
+
+	log.Print("[DEBUG] updated the user resource...")
+	return nil
+}
+
+// If the Update callback returns with or without an error, the full state is saved.
+// If the ID becomes blank, the resource is destroyed (even within an update, though this shouldn't happen except in error scenarios).
+// Partial mode is a mode that can be enabled by a callback that tells Terraform that it is possible for partial state to occur.
+// When this mode is enabled, the provider must explicitly tell Terraform what is safe to persist and what is not.
+func resourceMdbUserUpdate(data *schema.ResourceData, meta interface{}) error {
+	user := readUserRequest(data)
+
+	// only send the password if it was changed
+	if !data.HasChange("password") {
+		user.Password = ""
+	}
+
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.UpdateUser(data.Id(), user); err != nil {
+		return fmt.Errorf("failed to update user %s: %v", user.Username, err)
+	}
+	log.Printf("[DEBUG] updated user: %s", user.Username)
+
+	return resourceMdbUserRead(data, meta)
+}
+
+// If the Destroy callback returns without an error, the resource is assumed to be destroyed, and all state is removed.
+// If the Destroy callback returns with an error, the resource is assumed to still exist, and all prior state is preserved.
+// If the resource is already destroyed, this should not return an error.
+// This allows Terraform users to manually delete resources without breaking Terraform.
+func resourceMdbUserDelete(data *schema.ResourceData, meta interface{}) error {
+	username := data.Get("username").(string)
+
+	client, err := NewOpsManagerAPIClient(data, meta)
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteUser(data.Id())
+	if err != nil && !api.IsNotFound(err) {
+		return fmt.Errorf("failed to delete user %s: %v", username, err)
+	}
+	log.Printf("[DEBUG] deleted user: %s", username)
+
+	data.SetId("")
+	return nil
+}
+
+// readUserRequest builds an API request from the user's resource data
+func readUserRequest(data *schema.ResourceData) api.UserRequest {
+	username := data.Get("username").(string)
+
+	// default the user's name and email address to values derived from the username
+	firstName, lastName, emailAddress := util.TryExtractFirstLastNameAndEmail(username)
+	if v, ok := data.GetOk("first_name"); ok {
+		firstName = v.(string)
+	}
+	if v, ok := data.GetOk("last_name"); ok {
+		lastName = v.(string)
+	}
+	if v, ok := data.GetOk("email_address"); ok {
+		emailAddress = v.(string)
+	}
+
+	return api.UserRequest{
+		User: opsmanager.User{
+			Username:     username,
+			Password:     data.Get("password").(string),
+			FirstName:    firstName,
+			LastName:     lastName,
+			EmailAddress: emailAddress,
+		},
+		Roles: types.ReadUserRoles(data.Get("role").(*schema.Set).List()),
+	}
+}
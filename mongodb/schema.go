@@ -48,3 +48,50 @@ func WithAutomationSchema() map[string]*schema.Schema {
 		},
 	}
 }
+
+// WithMonitoringSchema appends MonitoringAgentConfigSchema schema to the specified schema map
+func WithMonitoringSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"monitoring": {
+			Type:     schema.TypeList,
+			Required: true,
+			Elem:     types.MonitoringAgentConfigSchema,
+		},
+	}
+}
+
+// WithBackupAgentSchema appends BackupAgentConfigSchema schema to the specified schema map
+func WithBackupAgentSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"backup": {
+			Type:     schema.TypeList,
+			Required: true,
+			Elem:     types.BackupAgentConfigSchema,
+		},
+	}
+}
+
+// WithBackupDaemonSchema appends BackupDaemonConfigSchema schema to the specified schema map
+func WithBackupDaemonSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"backup_daemon": {
+			Type:     schema.TypeList,
+			Required: true,
+			MaxItems: 1,
+			Elem:     types.BackupDaemonConfigSchema,
+		},
+	}
+}
+
+// WithOpsManagerAPISchema appends OpsManagerAPIConfigSchema schema to the specified schema map
+func WithOpsManagerAPISchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"opsmanager_api": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem:        types.OpsManagerAPIConfigSchema,
+			Description: "Ops Manager API connection params; defaults to the provider's 'opsmanager_api' block",
+		},
+	}
+}
@@ -4,13 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"os"
 	"strings"
 	"sync"
-
-	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
+	"sync/atomic"
+	"time"
 
 	"github.com/hashicorp/terraform/communicator/remote"
 	tfssh "github.com/hashicorp/terraform/communicator/ssh"
@@ -22,17 +21,29 @@ type Client struct {
 	ephemeralState *terraform.EphemeralState
 	communicator   *tfssh.Communicator
 	mutex          *sync.Mutex
+
+	// broken is set to 1 once the connection failed, and could not be re-established by the communicator
+	broken int32
+
+	// lastUsed the time, in Unix nanoseconds, at which the connection was last known to work
+	lastUsed int64
 }
 
 // NewClient creates a new SSH client
 func NewClient(params ...func(*Connection) error) (*Client, error) {
-	connInfo := &Connection{}
-	for _, paramFunc := range params {
-		err := paramFunc(connInfo)
-		util.PanicOnNonNilErr(err)
+	connInfo, err := newConnection(params...)
+	if err != nil {
+		return nil, err
 	}
+	return newClient(connInfo)
+}
 
-	state := connInfo.toEphemeralState()
+// newClient creates a new SSH client and connects it to the host described by connInfo
+func newClient(connInfo *Connection) (*Client, error) {
+	state, err := connInfo.toEphemeralState()
+	if err != nil {
+		return nil, fmt.Errorf("ssh.NewClient: could not build the connection info, err=%v", err)
+	}
 	ephemeral := &terraform.InstanceState{ID: "ssh", Attributes: make(map[string]string), Meta: make(map[string]interface{}), Tainted: false, Ephemeral: *state}
 	communicator, err := tfssh.New(ephemeral)
 	if err != nil {
@@ -52,10 +63,10 @@ func (c *Client) connect() error {
 	err := c.communicator.Connect(WithLogging())
 
 	if err != nil {
-		res := Result{Cmd: "communicator.Connect", Err: err}
-		return fmt.Errorf("ssh.connect: could not connect to remote host, err=%v", res)
+		return Result{Host: c.host(), Cmd: "communicator.Connect", Err: fmt.Errorf("could not connect to remote host: %v", err)}
 	}
 
+	c.touch()
 	return nil
 }
 
@@ -71,11 +82,12 @@ func (c *Client) UploadData(remotePath string, input io.Reader) (res Result) {
 	log.Printf("Uploading data to the remote host at: %s", remotePath)
 	if err := c.communicator.Upload(remotePath, input); err != nil {
 		errMsg := fmt.Errorf("ssh.UploadData: failed to upload data to remote, err=%v", err)
-		res = Result{Cmd: "Upload", Err: errMsg}
+		res = Result{Host: c.host(), Cmd: "Upload", Err: errMsg}
 		return
 	}
 
-	res = Result{Cmd: "Upload"}
+	c.touch()
+	res = Result{Host: c.host(), Cmd: "Upload"}
 	return
 }
 
@@ -91,16 +103,23 @@ func (c *Client) UploadFile(remotePath string, file *os.File) (res Result) {
 	log.Printf("Uploading file (%s) to the remote host at: %s", file.Name(), remotePath)
 	if err := c.communicator.Upload(remotePath, file); err != nil {
 		errMsg := fmt.Errorf("ssh.UploadFile: failed to upload file to remote host, err=%v", err)
-		res = Result{Cmd: "Upload", Err: errMsg}
+		res = Result{Host: c.host(), Cmd: "Upload", Err: errMsg}
 		return
 	}
 
-	res = Result{Cmd: "Upload"}
+	c.touch()
+	res = Result{Host: c.host(), Cmd: "Upload"}
 	return
 }
 
 // RunCommand executes a command on the remote host and returns the command's output, the SSH communicator's output and an error
-func (c *Client) RunCommand(command string) (res Result) {
+func (c *Client) RunCommand(command string) Result {
+	return c.RunCommandWithInput(command, nil)
+}
+
+// RunCommandWithInput executes a command on the remote host, passing the input to its stdin; use it for secrets,
+// which would otherwise be visible in the remote process list and in the command's debug output
+func (c *Client) RunCommandWithInput(command string, input io.Reader) (res Result) {
 	// ensure we correctly retrieve the output associated with this command
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -111,31 +130,79 @@ func (c *Client) RunCommand(command string) (res Result) {
 	// prepare command and output buffer
 	stdout := new(bytes.Buffer)
 	stderr := new(bytes.Buffer)
-	cmd := &remote.Cmd{Command: command, Stdout: stdout, Stderr: stderr}
+	cmd := &remote.Cmd{Command: command, Stdin: input, Stdout: stdout, Stderr: stderr}
 
 	if err := c.communicator.Start(cmd); err != nil {
+		// the communicator could not open a session, even after attempting to reconnect
+		c.markBroken()
 		errMsg := fmt.Errorf("ssh.RunCommand:: %v", err)
-		res = Result{Cmd: command, Err: errMsg, Stdout: bufferToString(stdout), Stderr: bufferToString(stderr)}
+		res = Result{Host: c.host(), Cmd: command, Err: errMsg, Stdout: bufferToString(stdout), Stderr: bufferToString(stderr)}
 		return
 	}
 
 	// await command completion
 	if err := cmd.Wait(); err != nil {
+		if isConnectionError(err) {
+			c.markBroken()
+		} else {
+			c.touch()
+		}
 		errMsg := fmt.Errorf("cmd.Wait: %v", err)
-		res = Result{Cmd: command, Err: errMsg, Stdout: bufferToString(stdout), Stderr: bufferToString(stderr)}
+		res = Result{Host: c.host(), Cmd: command, Err: errMsg, Stdout: bufferToString(stdout), Stderr: bufferToString(stderr)}
 		return
 	}
 
-	res = Result{Cmd: command, Stdout: bufferToString(stdout), Stderr: bufferToString(stderr)}
+	c.touch()
+	res = Result{Host: c.host(), Cmd: command, Stdout: bufferToString(stdout), Stderr: bufferToString(stderr)}
 	return
 }
 
-// bufferToString read all of a buffer's contents and return a string
-func bufferToString(buffer *bytes.Buffer) string {
-	data, err := ioutil.ReadAll(buffer)
-	if err != nil {
-		util.PanicOnNonNilErr(fmt.Errorf("ssh.bufferToString: could not save output, err=%v", err))
+// IsBroken returns true if a previous command failed because the connection was lost
+func (c *Client) IsBroken() bool {
+	return atomic.LoadInt32(&c.broken) == 1
+}
+
+// IdleFor returns how long ago the connection was last known to work
+func (c *Client) IdleFor() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&c.lastUsed)))
+}
+
+// markBroken records that the connection failed, so that the client is not reused
+func (c *Client) markBroken() {
+	atomic.StoreInt32(&c.broken, 1)
+}
+
+// touch records that the connection worked
+func (c *Client) touch() {
+	atomic.StoreInt64(&c.lastUsed, time.Now().UnixNano())
+}
+
+// Close disconnects the client from the remote host
+func (c *Client) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.communicator.Disconnect()
+}
+
+// isConnectionError returns true if a command failed because the connection was lost, rather than exiting with a non-zero status
+func isConnectionError(err error) bool {
+	exitErr, ok := err.(*remote.ExitError)
+	if !ok || exitErr.Err == nil {
+		return false
 	}
 
-	return strings.TrimSpace(string(data))
+	// commands which ran to completion report their exit status
+	_, exited := exitErr.Err.(interface{ ExitStatus() int })
+	return !exited
+}
+
+// host returns the remote host this client is connected to
+func (c *Client) host() string {
+	return c.ephemeralState.ConnInfo["host"]
+}
+
+// bufferToString read all of a buffer's contents and return a string
+func bufferToString(buffer *bytes.Buffer) string {
+	return strings.TrimSpace(buffer.String())
 }
@@ -1,9 +1,10 @@
 package ssh
 
 import (
+	"crypto/sha256"
 	"encoding/json"
-
-	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
+	"fmt"
+	"strconv"
 
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -20,6 +21,39 @@ type Connection struct {
 	Agent
 }
 
+// newConnection applies the passed parameters to a new *Connection
+func newConnection(params ...func(*Connection) error) (*Connection, error) {
+	connInfo := &Connection{}
+	for _, paramFunc := range params {
+		if err := paramFunc(connInfo); err != nil {
+			return nil, fmt.Errorf("ssh: invalid connection parameters, err=%v", err)
+		}
+	}
+	return connInfo, nil
+}
+
+// address describes the user, host, and port, and the bastion through which the host is reached, if any
+func (connection *Connection) address() string {
+	address := fmt.Sprintf("%s@%s:%d", connection.User, connection.Host, connection.Port)
+	if connection.BastionHost != "" {
+		address += fmt.Sprintf(" via %s@%s:%d", connection.BastionUser, connection.BastionHost, connection.BastionPort)
+	}
+	return address
+}
+
+// poolKey identifies connections which can share the same client: the same address, authenticated with the same credentials;
+// the credentials are hashed, since the key is kept in memory for the provider's lifetime
+func (connection *Connection) poolKey() string {
+	hash := sha256.New()
+	for _, secret := range []string{connection.Password, connection.PrivateKey, connection.HostKey,
+		connection.BastionPassword, connection.BastionPrivateKey, connection.BastionHostKey,
+		strconv.FormatBool(connection.Agent.Agent), connection.AgentIdentity} {
+		// prefix each value with its length, so that values cannot be shifted between fields
+		_, _ = fmt.Fprintf(hash, "%d:%s", len(secret), secret)
+	}
+	return fmt.Sprintf("%s/%x", connection.address(), hash.Sum(nil))
+}
+
 // WithHostParams accepts the minimal set of parameters required to make a connection
 func WithHostParams(user string, host string, port int) func(params *Connection) error {
 	return func(params *Connection) error {
@@ -38,6 +72,15 @@ func WithPrivateKey(privateKey string) func(params *Connection) error {
 	}
 }
 
+// WithPassword specifies the password to use for connecting to the target host,
+// through password or keyboard-interactive authentication
+func WithPassword(password string) func(params *Connection) error {
+	return func(params *Connection) error {
+		params.Password = password
+		return nil
+	}
+}
+
 // WithHostKey specifies the host keys of the target host
 func WithHostKey(hostKey string) func(params *Connection) error {
 	return func(params *Connection) error {
@@ -62,8 +105,10 @@ func (connection *Connection) toMap() (map[string]string, error) {
 }
 
 // toEphemeralState constructs a terraform.EphemeralState object to be used by the SSH communicator
-func (connection *Connection) toEphemeralState() *terraform.EphemeralState {
+func (connection *Connection) toEphemeralState() (*terraform.EphemeralState, error) {
 	connMap, err := connection.toMap()
-	util.PanicOnNonNilErr(err)
-	return &terraform.EphemeralState{ConnInfo: connMap}
+	if err != nil {
+		return nil, err
+	}
+	return &terraform.EphemeralState{ConnInfo: connMap}, nil
 }
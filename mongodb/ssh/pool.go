@@ -0,0 +1,94 @@
+package ssh
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// probeAfterIdle connections which were not used for longer than this are probed before being reused,
+// since they may have been dropped by the server (e.g., restarted, or timed out) or by a firewall
+const probeAfterIdle = 30 * time.Second
+
+// Pool shares SSH clients between all operations targeting the same host with the same credentials,
+// avoiding a new handshake for each of them
+type Pool struct {
+	mutex   sync.Mutex
+	entries map[string]*poolEntry
+}
+
+// poolEntry holds the client for a single host; its mutex ensures only one client is created per host,
+// without blocking operations on other hosts
+type poolEntry struct {
+	mutex  sync.Mutex
+	client *Client
+}
+
+// NewPool creates an empty SSH client pool
+func NewPool() *Pool {
+	return &Pool{entries: make(map[string]*poolEntry)}
+}
+
+// Get returns a connected client for the host described by the passed parameters; pooled clients are replaced
+// if a previous command failed because their connection was lost, or if they were idle and no longer respond
+func (p *Pool) Get(params ...func(*Connection) error) (*Client, error) {
+	connInfo, err := newConnection(params...)
+	if err != nil {
+		return nil, err
+	}
+	key := connInfo.poolKey()
+
+	p.mutex.Lock()
+	entry, ok := p.entries[key]
+	if !ok {
+		entry = &poolEntry{}
+		p.entries[key] = entry
+	}
+	p.mutex.Unlock()
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	if entry.client != nil {
+		if !entry.client.IsBroken() && entry.client.IdleFor() > probeAfterIdle {
+			if result := entry.client.RunCommand("true"); result.IsError() {
+				log.Printf("[DEBUG] the idle SSH connection to %s did not respond: %v", connInfo.address(), result.Err)
+				entry.client.markBroken()
+			}
+		}
+		if !entry.client.IsBroken() {
+			log.Printf("[DEBUG] reusing the SSH connection to: %s", connInfo.address())
+			return entry.client, nil
+		}
+
+		log.Printf("[WARN] the SSH connection to %s is broken, reconnecting", connInfo.address())
+		if err := entry.client.Close(); err != nil {
+			log.Printf("[DEBUG] could not close the SSH connection to %s: %v", connInfo.address(), err)
+		}
+		entry.client = nil
+	}
+
+	client, err := newClient(connInfo)
+	if err != nil {
+		return nil, err
+	}
+	entry.client = client
+	return client, nil
+}
+
+// Close disconnects all pooled clients
+func (p *Pool) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for key, entry := range p.entries {
+		entry.mutex.Lock()
+		if entry.client != nil {
+			if err := entry.client.Close(); err != nil {
+				log.Printf("[DEBUG] could not close a pooled SSH connection: %v", err)
+			}
+		}
+		entry.mutex.Unlock()
+		delete(p.entries, key)
+	}
+}
@@ -0,0 +1,97 @@
+package ssh
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/communicator/remote"
+)
+
+type exitStatusError int
+
+func (e exitStatusError) Error() string   { return "process exited" }
+func (e exitStatusError) ExitStatus() int { return int(e) }
+
+func TestPoolKey_unit(t *testing.T) {
+	key := func(params ...func(*Connection) error) string {
+		connInfo, err := newConnection(append([]func(*Connection) error{WithHostParams("admin", "host", 22)}, params...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return connInfo.poolKey()
+	}
+	withBastion := func(params *Connection) error {
+		params.BastionUser = "jump"
+		params.BastionHost = "bastion"
+		params.BastionPort = 22
+		return nil
+	}
+
+	if key(WithPassword("secret")) != key(WithPassword("secret")) {
+		t.Error("identical connections should share a key")
+	}
+	if key(WithPassword("secret")) == key(WithPassword("other")) {
+		t.Error("connections with different passwords should not share a key")
+	}
+	if key(WithPrivateKey("key1")) == key(WithPrivateKey("key2")) {
+		t.Error("connections with different private keys should not share a key")
+	}
+	if key(WithPassword("secret")) == key(WithPrivateKey("secret")) {
+		t.Error("credentials should not be interchangeable between fields")
+	}
+	if key() == key(withBastion) {
+		t.Error("connections through a bastion should not share a key with direct connections")
+	}
+	if strings.Contains(key(WithPassword("secret")), "secret") {
+		t.Error("the key should not contain credentials")
+	}
+}
+
+func TestPoolReusesClients_unit(t *testing.T) {
+	params := []func(*Connection) error{WithHostParams("admin", "host", 22), WithPassword("secret")}
+	connInfo, err := newConnection(params...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// recently used clients are reused without being probed
+	client := &Client{mutex: &sync.Mutex{}}
+	client.touch()
+	if client.IdleFor() > probeAfterIdle {
+		t.Fatalf("a client which was just used should not be idle")
+	}
+	pool := NewPool()
+	pool.entries[connInfo.poolKey()] = &poolEntry{client: client}
+
+	for i := 0; i < 2; i++ {
+		pooled, err := pool.Get(params...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pooled != client {
+			t.Errorf("expected the pooled client to be reused")
+		}
+	}
+}
+
+func TestIsConnectionError_unit(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"non-zero exit status", &remote.ExitError{ExitStatus: 1, Err: exitStatusError(1)}, false},
+		{"successful exit", &remote.ExitError{}, false},
+		{"connection lost", &remote.ExitError{ExitStatus: -1, Err: errors.New("EOF")}, true},
+		{"other error", errors.New("failed"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isConnectionError(tt.err); got != tt.want {
+				t.Errorf("isConnectionError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,15 +8,39 @@ import (
 
 // Result represents the result of a SSH command
 type Result struct {
+	Host   string
 	Cmd    string
 	Stdout string
 	Stderr string
 	Err    error
 }
 
-// Error implementation of the error interface
+// Error implementation of the error interface; describes the failed command, the host it ran on, and its output
 func (r Result) Error() string {
-	return fmt.Sprintf("ssh.%s: unexpected error=%s", r.Cmd, r.GetDebugInfo())
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "command '%s' failed", r.Cmd)
+	if r.Host != "" {
+		_, _ = fmt.Fprintf(&sb, " on %s", r.Host)
+	}
+	_, _ = fmt.Fprintf(&sb, ": %v", r.Err)
+
+	if r.Stdout != "" {
+		_, _ = fmt.Fprintf(&sb, "\nstdout: %s", r.Stdout)
+	}
+
+	if r.Stderr != "" {
+		_, _ = fmt.Fprintf(&sb, "\nstderr: %s", r.Stderr)
+	}
+
+	return sb.String()
+}
+
+// ErrorOrNil returns the Result as an error if the command has failed, or nil otherwise
+func (r Result) ErrorOrNil() error {
+	if r.IsError() {
+		return r
+	}
+	return nil
 }
 
 // IsError true if the Result contains an error
@@ -47,11 +71,3 @@ func (r *Result) GetDebugInfo() string {
 func (r *Result) Debug() {
 	log.Println("[DEBUG] Executed" + r.GetDebugInfo())
 }
-
-// PanicOnError panic if a result has completed with an error (useful in situations where errors are not recoverable,
-// but we want to allow error handling at an upper layer
-func PanicOnError(r Result) {
-	if r.IsError() {
-		log.Fatalf("unexpected error for %s; err=%v", r.GetDebugInfo(), r.Err)
-	}
-}
@@ -3,32 +3,34 @@ package ssh
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
 )
 
-// NewOpenPortCheckerFunc constructs a function based on the specified ssh.Client, which checks if the specified port is open
-func NewOpenPortCheckerFunc(client *Client) func(port int) Result {
-	return func(port int) Result {
-		return client.RunCommand(fmt.Sprintf("(netstat -nl | grep -q %d) && echo open || echo closed", port))
+// NewHTTPCheckerFunc constructs a function based on the specified ssh.Client, which checks if the specified URL
+// can be successfully retrieved from the remote host; certificates are not verified, since the URL usually points to localhost
+func NewHTTPCheckerFunc(client *Client) func(url string) Result {
+	return func(url string) Result {
+		return client.RunCommand(fmt.Sprintf("(curl -ksf -o /dev/null --max-time 10 '%s') && echo ready || echo unavailable", url))
 	}
 }
 
-// IsPortOpen returns a StateRefreshFunc for determining if the specified port is open
-func IsPortOpen(portChecker func(port int) Result, port int) resource.StateRefreshFunc {
+// IsHTTPReady returns a StateRefreshFunc for determining if the specified URL can be retrieved
+func IsHTTPReady(httpChecker func(url string) Result, url string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
-		result := portChecker(port)
+		result := httpChecker(url)
 		if result.IsError() {
 			return nil, "", result
 		}
 
-		if result.Stdout == "open" {
-			return port, "open", nil
+		if result.Stdout == "ready" {
+			return url, "ready", nil
 		}
 
-		if result.Stdout == "closed" {
-			return nil, "closed", nil
+		if result.Stdout == "unavailable" {
+			return nil, "unavailable", nil
 		}
 
 		if result.Stderr != "" {
@@ -39,16 +41,16 @@ func IsPortOpen(portChecker func(port int) Result, port int) resource.StateRefre
 	}
 }
 
-// WaitForOpenPort returns when the specified port is open, or with an error if the operation times out
-func WaitForOpenPort(portChecker func(port int) Result, port int) error {
+// WaitForHTTPReady returns when the specified URL can be retrieved, or with an error if the operation times out
+func WaitForHTTPReady(httpChecker func(url string) Result, url string, timeout time.Duration) error {
 	stateConf := &resource.StateChangeConf{
-		Pending: []string{"closed"},
-		Target:  []string{"open"},
-		Refresh: IsPortOpen(portChecker, port),
-		Timeout: util.PortAvailableTimeout,
+		Pending: []string{"unavailable"},
+		Target:  []string{"ready"},
+		Refresh: IsHTTPReady(httpChecker, url),
+		Timeout: timeout,
 	}
 
-	log.Printf("[DEBUG] Waiting for port to be opened: %d", port)
+	log.Printf("[DEBUG] Waiting for URL to be ready: %s", url)
 	_, err := stateConf.WaitForState()
 
 	return err
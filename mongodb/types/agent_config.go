@@ -0,0 +1,423 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+const (
+	// AgentInstallPackage installs an agent from the .deb or .rpm package served by Ops Manager
+	AgentInstallPackage = "package"
+
+	// AgentInstallTarball unpacks an agent from the tar.gz archive served by Ops Manager
+	AgentInstallTarball = "tarball"
+)
+
+// AgentType describes how an agent served by Ops Manager is packaged, installed, and configured
+type AgentType struct {
+	// Name identifies the agent in download URLs and filenames, in the resource schema,
+	// and in the AgentConfig struct tags which map its fields to keys in the agent's config file
+	Name string
+
+	// APIType identifies the agent in the Ops Manager agents API
+	APIType string
+
+	// PackageName and ServiceName identify the package which installs the agent, and the system service which it registers
+	PackageName string
+	ServiceName string
+
+	// ServiceUser the user which runs the agent
+	ServiceUser string
+
+	// ConfigFilename the name of the config file shipped in the tar.gz archive
+	ConfigFilename string
+
+	// LogFileKey the config key which sets the agent's log file, if supported
+	LogFileKey string
+
+	// HostPortBaseURL the agent is pointed to Ops Manager's host:port, along with an 'https' flag, instead of its base URL
+	HostPortBaseURL bool
+
+	// the paths used by package installs
+	PackageConfigFilename string
+	PackageBinaryFilename string
+	PackageLogFilename    string
+}
+
+// AutomationAgent installs the automation agent, which also monitors and backs up the processes it manages
+var AutomationAgent = AgentType{
+	Name:                  "automation",
+	APIType:               "AUTOMATION",
+	PackageName:           "mongodb-mms-automation-agent-manager",
+	ServiceName:           "mongodb-mms-automation-agent",
+	ServiceUser:           "mongod",
+	ConfigFilename:        "local.config",
+	LogFileKey:            "logFile",
+	PackageConfigFilename: "/etc/mongodb-mms/automation-agent.config",
+	PackageBinaryFilename: "/opt/mongodb-mms-automation/bin/mongodb-mms-automation-agent",
+	PackageLogFilename:    "/var/log/mongodb-mms-automation/automation-agent.log",
+}
+
+// MonitoringAgent installs the standalone monitoring agent, used by Ops Manager versions which predate the unified automation agent
+var MonitoringAgent = AgentType{
+	Name:                  "monitoring",
+	APIType:               "MONITORING",
+	PackageName:           "mongodb-mms-monitoring-agent",
+	ServiceName:           "mongodb-mms-monitoring-agent",
+	ServiceUser:           "mongodb-mms-agent",
+	ConfigFilename:        "monitoring-agent.config",
+	PackageConfigFilename: "/etc/mongodb-mms/monitoring-agent.config",
+	PackageBinaryFilename: "/usr/bin/mongodb-mms-monitoring-agent",
+	PackageLogFilename:    "/var/log/mongodb-mms/monitoring-agent.log",
+}
+
+// BackupAgent installs the standalone backup agent, used by Ops Manager versions which predate the unified automation agent
+var BackupAgent = AgentType{
+	Name:                  "backup",
+	APIType:               "BACKUP",
+	PackageName:           "mongodb-mms-backup-agent",
+	ServiceName:           "mongodb-mms-backup-agent",
+	ServiceUser:           "mongodb-mms-agent",
+	ConfigFilename:        "local.config",
+	HostPortBaseURL:       true,
+	PackageConfigFilename: "/etc/mongodb-mms/backup-agent.config",
+	PackageBinaryFilename: "/usr/bin/mongodb-mms-backup-agent",
+	PackageLogFilename:    "/var/log/mongodb-mms/backup-agent.log",
+}
+
+// AgentConfig holder for Agent Config; each field is mapped to a key in the config file of the agent types which support it,
+// through the struct tag named after the agent type
+type AgentConfig struct {
+	Type                                 AgentType              `json:"-"`
+	MMSBaseURL                           string                 `json:"mms_base_url,omitempty" automation:"mmsBaseUrl" monitoring:"mmsBaseUrl" backup:"mothership"`
+	WorkDir                              string                 `json:"workdir,omitempty"`
+	Version                              string                 `json:"version,omitempty"`
+	MMSGroupID                           string                 `json:"mms_group_id,omitempty" automation:"mmsGroupId" monitoring:"mmsGroupId" backup:"mmsGroupId"`
+	MMSAgentAPIKey                       string                 `json:"mms_agent_api_key,omitempty" automation:"mmsApiKey" monitoring:"mmsApiKey" backup:"mmsApiKey"`
+	InstallMethod                        string                 `json:"install_method,omitempty"`
+	Platform                             string                 `json:"platform,omitempty"`
+	SSLTrustedMMSServerCertificate       string                 `json:"ssl_trusted_mms_server_certificate,omitempty" automation:"sslTrustedMMSServerCertificate" monitoring:"sslTrustedMMSServerCertificate" backup:"sslTrustedMMSServerCertificate"`
+	SSLRequireValidMMSServerCertificates bool                   `json:"ssl_require_valid_mms_server_certificates,omitempty" automation:"sslRequireValidMMSServerCertificates" monitoring:"sslRequireValidMMSServerCertificates" backup:"sslRequireValidMMSServerCertificates"`
+	HTTPProxy                            string                 `json:"http_proxy,omitempty" automation:"httpProxy" monitoring:"httpProxy" backup:"httpProxy"`
+	MaxLogFileSize                       int                    `json:"max_log_file_size,omitempty" automation:"maxLogFileSize"`
+	MaxLogFileDurationHrs                int                    `json:"max_log_file_duration_hrs,omitempty" automation:"maxLogFileDurationHrs"`
+	Overrides                            map[string]interface{} `json:"overrides,omitempty"`
+	InstalledVersion                     string                 `json:"installed_version,omitempty"`
+	AgentHostname                        string                 `json:"agent_hostname,omitempty"`
+	LastPing                             string                 `json:"last_ping,omitempty"`
+	Running                              bool                   `json:"running,omitempty"`
+}
+
+// ReadAgentConfig parses a singleton list of agent config resources (e.g. AutomationAgentConfigSchema) as a AgentConfig type
+func ReadAgentConfig(agentType AgentType, list []interface{}) AgentConfig {
+	// read the connection params
+	cfg := &AgentConfig{Type: agentType}
+	data := list[0].(map[string]interface{})
+	if v, ok := ReadString(data, "mms_base_url"); ok {
+		cfg.MMSBaseURL = v
+	}
+	if v, ok := ReadString(data, "mms_group_id"); ok {
+		cfg.MMSGroupID = v
+	}
+	if v, ok := ReadString(data, "mms_agent_api_key"); ok {
+		cfg.MMSAgentAPIKey = v
+	}
+	if v, ok := ReadString(data, "version"); ok {
+		cfg.Version = v
+	}
+	if v, ok := ReadString(data, "workdir"); ok {
+		cfg.WorkDir = v
+	}
+	if v, ok := ReadString(data, "install_method"); ok {
+		cfg.InstallMethod = v
+	}
+	if v, ok := ReadString(data, "platform"); ok {
+		cfg.Platform = v
+	}
+	if v, ok := ReadString(data, "ssl_trusted_mms_server_certificate"); ok {
+		cfg.SSLTrustedMMSServerCertificate = v
+	}
+	if v, ok := ReadBool(data, "ssl_require_valid_mms_server_certificates"); ok {
+		cfg.SSLRequireValidMMSServerCertificates = v
+	}
+	if v, ok := ReadString(data, "http_proxy"); ok {
+		cfg.HTTPProxy = v
+	}
+	if v, ok := ReadInt(data, "max_log_file_size"); ok {
+		cfg.MaxLogFileSize = v
+	}
+	if v, ok := ReadInt(data, "max_log_file_duration_hrs"); ok {
+		cfg.MaxLogFileDurationHrs = v
+	}
+	if v, ok := ReadStringMap(data, "overrides"); ok {
+		cfg.Overrides = v
+	}
+	if v, ok := ReadString(data, "installed_version"); ok {
+		cfg.InstalledVersion = v
+	}
+	if v, ok := ReadString(data, "agent_hostname"); ok {
+		cfg.AgentHostname = v
+	}
+	if v, ok := ReadString(data, "last_ping"); ok {
+		cfg.LastPing = v
+	}
+	if v, ok := ReadBool(data, "running"); ok {
+		cfg.Running = v
+	}
+	return *cfg
+}
+
+// AutomationAgentConfigSchema holds a minimal set of parameters required to start an automation agent
+var AutomationAgentConfigSchema = &schema.Resource{
+	Schema: agentConfigSchema("/var/lib/mongodb-mms-automation", map[string]*schema.Schema{
+		// log rotation thresholds, in bytes and hours; not set if 0
+		"max_log_file_size": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"max_log_file_duration_hrs": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+	}),
+}
+
+// MonitoringAgentConfigSchema holds a minimal set of parameters required to start a legacy monitoring agent
+var MonitoringAgentConfigSchema = &schema.Resource{
+	Schema: agentConfigSchema("/var/lib/mongodb-mms-monitoring-agent", nil),
+}
+
+// BackupAgentConfigSchema holds a minimal set of parameters required to start a legacy backup agent
+var BackupAgentConfigSchema = &schema.Resource{
+	Schema: agentConfigSchema("/var/lib/mongodb-mms-backup-agent", nil),
+}
+
+// agentConfigSchema returns the parameters shared by all agent types, along with the specified agent-specific parameters
+func agentConfigSchema(defaultWorkDir string, extra map[string]*schema.Schema) map[string]*schema.Schema {
+	params := map[string]*schema.Schema{
+		"mms_base_url": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"mms_group_id": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"mms_agent_api_key": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"version": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  LatestAgentVersion,
+			ForceNew: true,
+		},
+		"workdir": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  defaultWorkDir,
+			ForceNew: true,
+		},
+		"install_method": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      AgentInstallTarball,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{AgentInstallPackage, AgentInstallTarball}, false),
+		},
+		// the platform segment of the agent's filename (e.g. linux_x86_64, arm64.ubuntu1804, x86_64.rhel7);
+		// detected from the remote host if not specified
+		"platform": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		},
+		// the contents of the CA which signed Ops Manager's HTTPS certificate
+		"ssl_trusted_mms_server_certificate": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"ssl_require_valid_mms_server_certificates": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"http_proxy": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"overrides": {
+			Type:     schema.TypeMap,
+			Optional: true,
+		},
+		// the version of the agent running on the host, which Ops Manager may upgrade
+		"installed_version": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		// the hostname under which the agent reports to Ops Manager, and the last time it did so
+		"agent_hostname": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"last_ping": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		// whether the agent should be running; refreshed with the agent's actual state, so that an agent which was stopped
+		// out of band is restarted on the next apply
+		"running": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
+	return params
+}
+
+// IsPackage returns true if the agent is installed from a package and managed as a system service
+func (cfg AgentConfig) IsPackage() bool {
+	return cfg.InstallMethod == AgentInstallPackage
+}
+
+// ConfigFilename returns the path to the process's config filename
+func (cfg AgentConfig) ConfigFilename() string {
+	if cfg.IsPackage() {
+		return cfg.Type.PackageConfigFilename
+	}
+
+	return path.Join(cfg.WorkDir, cfg.Type.ConfigFilename)
+}
+
+// BinaryFilename returns the path to the agent's executable
+func (cfg AgentConfig) BinaryFilename() string {
+	if cfg.IsPackage() {
+		return cfg.Type.PackageBinaryFilename
+	}
+
+	return path.Join(cfg.WorkDir, fmt.Sprintf("mongodb-mms-%s-agent", cfg.Type.Name))
+}
+
+// LogFilename returns the path to the process's log filename
+func (cfg AgentConfig) LogFilename() string {
+	if cfg.IsPackage() {
+		return cfg.Type.PackageLogFilename
+	}
+
+	return path.Join(cfg.WorkDir, fmt.Sprintf("%s-agent.log", cfg.Type.Name))
+}
+
+// SSLTrustedMMSServerCertificateFilename returns the path to the CA which signed Ops Manager's HTTPS certificate
+func (cfg AgentConfig) SSLTrustedMMSServerCertificateFilename() string {
+	if cfg.IsPackage() {
+		return fmt.Sprintf("/etc/mongodb-mms/%s-agent-ca.pem", cfg.Type.Name)
+	}
+
+	return path.Join(cfg.WorkDir, "mms-ca.pem")
+}
+
+// DownloadURL returns the URL from which Ops Manager serves the specified agent archive or package
+func (cfg AgentConfig) DownloadURL(filename string) string {
+	return fmt.Sprintf("%s/download/agent/%s/%s", cfg.MMSBaseURL, cfg.Type.Name, filename)
+}
+
+// DownloadFilename returns the name of the archive or package served by Ops Manager, in the specified format (tar.gz, deb, rpm);
+// the installed version is downloaded, if it was resolved
+func (cfg AgentConfig) DownloadFilename(format string) string {
+	version := cfg.Version
+	if cfg.InstalledVersion != "" {
+		version = cfg.InstalledVersion
+	}
+
+	switch format {
+	case "deb":
+		return fmt.Sprintf("%s_%s_%s.deb", cfg.Type.PackageName, version, cfg.Platform)
+	case "rpm":
+		return fmt.Sprintf("%s-%s.%s.rpm", cfg.Type.PackageName, version, cfg.Platform)
+	default:
+		return fmt.Sprintf("mongodb-mms-%s-agent-%s.%s.tar.gz", cfg.Type.Name, version, cfg.Platform)
+	}
+}
+
+// VersionCommand returns a command which prints the version of the installed agent
+func (cfg AgentConfig) VersionCommand() string {
+	return fmt.Sprintf("%s -version", cfg.BinaryFilename())
+}
+
+// InstallCommand returns a command which installs the agent package stored at the specified path
+func (cfg AgentConfig) InstallCommand(packagePath string) string {
+	if path.Ext(packagePath) == ".deb" {
+		return fmt.Sprintf("dpkg -i --force-confold %s", packagePath)
+	}
+
+	return fmt.Sprintf("rpm -U --replacepkgs %s", packagePath)
+}
+
+// UninstallCommand returns a command which removes the agent package, if it is installed
+func (cfg AgentConfig) UninstallCommand() string {
+	return fmt.Sprintf("bash -c \"if command -v dpkg >/dev/null 2>&1 && dpkg -s %[1]s >/dev/null 2>&1; then dpkg -P %[1]s; elif command -v rpm >/dev/null 2>&1 && rpm -q %[1]s >/dev/null 2>&1; then rpm -e %[1]s; fi\"", cfg.Type.PackageName)
+}
+
+// EnableServiceCommand returns a command which starts the agent service on boot;
+// init.d scripts are registered by the package itself
+func (cfg AgentConfig) EnableServiceCommand() string {
+	return fmt.Sprintf("bash -c \"if [ -d /run/systemd/system ]; then systemctl enable %s; fi\"", cfg.Type.ServiceName)
+}
+
+// ServiceCommand returns a command which performs the specified action (start, stop, restart) on the agent service,
+// using systemd if it manages the remote host, or the init.d script otherwise
+func (cfg AgentConfig) ServiceCommand(action string) string {
+	return fmt.Sprintf("bash -c \"if [ -d /run/systemd/system ]; then systemctl %[1]s %[2]s; else /etc/init.d/%[2]s %[1]s; fi\"", action, cfg.Type.ServiceName)
+}
+
+// SetBaseURL points the agent to Ops Manager, in the format expected by its type
+func (cfg AgentConfig) SetBaseURL(props *PropertiesFile) error {
+	key := cfg.GetAgentConfigTag("MMSBaseURL")
+	if !cfg.Type.HostPortBaseURL {
+		props.SetPropertyValue(key, cfg.MMSBaseURL)
+		return nil
+	}
+
+	baseURL, err := url.Parse(cfg.MMSBaseURL)
+	if err != nil || baseURL.Host == "" {
+		return fmt.Errorf("invalid Ops Manager base URL: %s", cfg.MMSBaseURL)
+	}
+	props.SetPropertyValue(key, baseURL.Host)
+	props.SetPropertyValue("https", strconv.FormatBool(baseURL.Scheme == "https"))
+	return nil
+}
+
+// GetBaseURL reads back the Ops Manager base URL set by SetBaseURL; returns false if it was not set
+func (cfg AgentConfig) GetBaseURL(props *PropertiesFile) (string, bool) {
+	value, ok := props.GetPropertyValue(cfg.GetAgentConfigTag("MMSBaseURL"))
+	if !ok || !cfg.Type.HostPortBaseURL {
+		return value, ok
+	}
+
+	scheme := "http"
+	if https, _ := props.GetPropertyValue("https"); https == "true" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, value), true
+}
+
+// GetAgentConfigTag given a valid AgentConfig struct field name, returns the config key of the agent's type;
+// returns an empty string if the agent does not support the field
+func (cfg AgentConfig) GetAgentConfigTag(fieldName string) string {
+	t := reflect.TypeOf(cfg)
+	field, _ := t.FieldByName(fieldName)
+	return field.Tag.Get(cfg.Type.Name)
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestAgentConfigTypes_unit(t *testing.T) {
+	automation := AgentConfig{Type: AutomationAgent, WorkDir: "/var/lib/mongodb-mms-automation", Version: "latest", Platform: "linux_x86_64"}
+	if f := automation.ConfigFilename(); f != "/var/lib/mongodb-mms-automation/local.config" {
+		t.Errorf("unexpected config filename: %s", f)
+	}
+	if tag := automation.GetAgentConfigTag("MaxLogFileSize"); tag != "maxLogFileSize" {
+		t.Errorf("unexpected tag: %s", tag)
+	}
+
+	monitoring := AgentConfig{Type: MonitoringAgent, WorkDir: "/opt/agent", InstalledVersion: "6.6.2.464-1", Platform: "linux_x86_64"}
+	if f := monitoring.DownloadFilename("tar.gz"); f != "mongodb-mms-monitoring-agent-6.6.2.464-1.linux_x86_64.tar.gz" {
+		t.Errorf("unexpected download filename: %s", f)
+	}
+	if f := monitoring.BinaryFilename(); f != "/opt/agent/mongodb-mms-monitoring-agent" {
+		t.Errorf("unexpected binary filename: %s", f)
+	}
+	if tag := monitoring.GetAgentConfigTag("MaxLogFileSize"); tag != "" {
+		t.Errorf("expected log rotation to be unsupported, got: %s", tag)
+	}
+}
+
+func TestAgentConfigBaseURL_unit(t *testing.T) {
+	backup := AgentConfig{Type: BackupAgent, MMSBaseURL: "https://opsmanager.example.com:8443"}
+	props, err := NewPropertiesFile("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := backup.SetBaseURL(props); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, _ := props.GetPropertyValue("mothership"); v != "opsmanager.example.com:8443" {
+		t.Errorf("unexpected mothership: %s", v)
+	}
+	if v, ok := backup.GetBaseURL(props); !ok || v != backup.MMSBaseURL {
+		t.Errorf("unexpected base URL: %s", v)
+	}
+
+	automation := AgentConfig{Type: AutomationAgent, MMSBaseURL: "http://opsmanager.example.com:8080"}
+	props, err = NewPropertiesFile("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := automation.SetBaseURL(props); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, _ := props.GetPropertyValue("mmsBaseUrl"); v != automation.MMSBaseURL {
+		t.Errorf("unexpected mmsBaseUrl: %s", v)
+	}
+}
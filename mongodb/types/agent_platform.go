@@ -0,0 +1,83 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AgentPlatform the architecture and Linux distribution of a host on which an agent is installed
+type AgentPlatform struct {
+	Arch          string
+	Distro        string
+	DistroVersion string
+}
+
+// AgentPlatformCommand prints the remote host's architecture, distribution ID, and distribution version,
+// in the format parsed by ParseAgentPlatform
+const AgentPlatformCommand = "bash -c '. /etc/os-release 2>/dev/null; echo $(uname -m) ${ID:-unknown} ${VERSION_ID:-0}'"
+
+// ParseAgentPlatform parses the output of AgentPlatformCommand
+func ParseAgentPlatform(output string) (AgentPlatform, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 3 {
+		return AgentPlatform{}, fmt.Errorf("could not parse the platform from: %q", output)
+	}
+
+	return AgentPlatform{Arch: fields[0], Distro: fields[1], DistroVersion: fields[2]}, nil
+}
+
+// Name returns the platform's name, as used in the filenames of the agents served by Ops Manager, in the specified format (tar.gz, deb, rpm)
+func (p AgentPlatform) Name(format string) (string, error) {
+	var arm bool
+	switch p.Arch {
+	case "x86_64", "amd64":
+	case "aarch64", "arm64":
+		arm = true
+	default:
+		return "", fmt.Errorf("unsupported architecture: %s", p.Arch)
+	}
+
+	distro, err := p.distroName()
+	if err != nil && (format != "tar.gz" || arm) {
+		return "", err
+	}
+
+	switch format {
+	case "deb":
+		if arm {
+			return fmt.Sprintf("arm64.%s", distro), nil
+		}
+		return fmt.Sprintf("amd64.%s", distro), nil
+	case "rpm":
+		if arm {
+			return fmt.Sprintf("aarch64.%s", distro), nil
+		}
+		return fmt.Sprintf("x86_64.%s", distro), nil
+	default:
+		// the generic x86_64 archive runs on any distribution; ARM builds are distribution specific
+		if arm {
+			return fmt.Sprintf("%s_aarch64", distro), nil
+		}
+		return "linux_x86_64", nil
+	}
+}
+
+// distroName returns the distribution's name and version, as used in the filenames of the agents served by Ops Manager
+func (p AgentPlatform) distroName() (string, error) {
+	major := strings.Split(p.DistroVersion, ".")[0]
+	switch p.Distro {
+	case "ubuntu":
+		// e.g. ubuntu1804
+		return "ubuntu" + strings.Replace(p.DistroVersion, ".", "", -1), nil
+	case "debian":
+		return "debian" + major, nil
+	case "rhel", "centos", "ol", "rocky", "almalinux":
+		return "rhel" + major, nil
+	case "amzn":
+		return "amzn" + major, nil
+	case "sles", "opensuse-leap":
+		return "suse" + major, nil
+	default:
+		return "", fmt.Errorf("unsupported distribution: %s %s", p.Distro, p.DistroVersion)
+	}
+}
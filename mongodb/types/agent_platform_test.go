@@ -0,0 +1,42 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestAgentPlatformName_unit(t *testing.T) {
+	tests := []struct {
+		output   string
+		format   string
+		expected string
+	}{
+		{"x86_64 ubuntu 18.04", "deb", "amd64.ubuntu1804"},
+		{"aarch64 ubuntu 20.04", "deb", "arm64.ubuntu2004"},
+		{"x86_64 centos 7", "rpm", "x86_64.rhel7"},
+		{"aarch64 rhel 8.4", "rpm", "aarch64.rhel8"},
+		{"aarch64 amzn 2", "tar.gz", "amzn2_aarch64"},
+		{"x86_64 unknown 0", "tar.gz", "linux_x86_64"},
+	}
+
+	for _, test := range tests {
+		platform, err := ParseAgentPlatform(test.output)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		name, err := platform.Name(test.format)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", test.output, err)
+		}
+		if name != test.expected {
+			t.Errorf("expected %q for %q, got: %q", test.expected, test.output, name)
+		}
+	}
+
+	// ARM builds and packages are distribution specific
+	for _, output := range []string{"aarch64 unknown 0", "x86_64 unknown 0", "ppc64le ubuntu 18.04"} {
+		platform, _ := ParseAgentPlatform(output)
+		if _, err := platform.Name("deb"); err == nil {
+			t.Errorf("expected an error for %q", output)
+		}
+	}
+}
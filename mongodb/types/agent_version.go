@@ -0,0 +1,21 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// LatestAgentVersion downloads whichever agent version Ops Manager currently serves
+const LatestAgentVersion = "latest"
+
+// agentVersionPattern matches the version printed by an agent's -version flag (e.g. "MongoDB Automation Agent version: 10.2.0.5851-1 (git: ...)")
+var agentVersionPattern = regexp.MustCompile(`[Vv]ersion:?\s+v?([0-9][0-9A-Za-z.\-]*)`)
+
+// ParseAgentVersion parses the output of an agent's -version flag
+func ParseAgentVersion(output string) (string, error) {
+	match := agentVersionPattern.FindStringSubmatch(output)
+	if match == nil {
+		return "", fmt.Errorf("could not parse the agent version from: %q", output)
+	}
+	return match[1], nil
+}
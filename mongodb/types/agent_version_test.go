@@ -0,0 +1,19 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestParseAgentVersion_unit(t *testing.T) {
+	version, err := ParseAgentVersion("MongoDB Automation Agent version: 10.2.0.5851-1 (git: 7d7f5e0)\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != "10.2.0.5851-1" {
+		t.Errorf("unexpected version: %s", version)
+	}
+
+	if _, err := ParseAgentVersion("command not found"); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,206 @@
+package types
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// BackupDaemonConfig holder for Backup Daemon config
+type BackupDaemonConfig struct {
+	Binary              string                 `json:"binary,omitempty"`
+	WorkDir             string                 `json:"workdir,omitempty"`
+	MongoURI            string                 `json:"mongo_uri,omitempty"`
+	EncryptionKey       string                 `json:"encryption_key,omitempty"`
+	Port                int                    `json:"port,omitempty"`
+	CentralURL          string                 `json:"central_url,omitempty"`
+	Overrides           map[string]interface{} `json:"overrides,omitempty"`
+	HeadDirectory       string                 `json:"head_directory,omitempty"`
+	Machine             string                 `json:"machine,omitempty"`
+	NumWorkers          int                    `json:"num_workers,omitempty"`
+	RetainEncryptionKey bool                   `json:"retain_encryption_key,omitempty"`
+	HTTPSPEMKey         string                 `json:"https_pem_key,omitempty"`
+	HTTPSPEMKeyPassword string                 `json:"https_pem_key_password,omitempty"`
+	HTTPSCA             string                 `json:"https_ca,omitempty"`
+	HTTPSPort           int                    `json:"https_port,omitempty"`
+}
+
+// ReadBackupDaemonConfig parses a singleton list of BackupDaemonConfigSchema resources as a BackupDaemonConfig type
+func ReadBackupDaemonConfig(list []interface{}) BackupDaemonConfig {
+	// read the connection params
+	cfg := &BackupDaemonConfig{}
+	data := list[0].(map[string]interface{})
+	if v, ok := ReadString(data, "binary"); ok {
+		cfg.Binary = v
+	}
+	if v, ok := ReadString(data, "workdir"); ok {
+		cfg.WorkDir = v
+	}
+	if v, ok := ReadString(data, "mongo_uri"); ok {
+		cfg.MongoURI = v
+	}
+	if v, ok := ReadString(data, "encryption_key"); ok {
+		cfg.EncryptionKey = v
+	}
+	if v, ok := ReadInt(data, "port"); ok {
+		cfg.Port = v
+	}
+	if v, ok := ReadString(data, "central_url"); ok {
+		cfg.CentralURL = v
+	}
+	if v, ok := ReadStringMap(data, "overrides"); ok {
+		cfg.Overrides = v
+	}
+	if v, ok := ReadString(data, "head_directory"); ok {
+		cfg.HeadDirectory = v
+	}
+	if v, ok := ReadString(data, "machine"); ok {
+		cfg.Machine = v
+	}
+	if v, ok := ReadInt(data, "num_workers"); ok {
+		cfg.NumWorkers = v
+	}
+	if v, ok := ReadBool(data, "retain_encryption_key"); ok {
+		cfg.RetainEncryptionKey = v
+	}
+	if v, ok := ReadString(data, "https_pem_key"); ok {
+		cfg.HTTPSPEMKey = v
+	}
+	if v, ok := ReadString(data, "https_pem_key_password"); ok {
+		cfg.HTTPSPEMKeyPassword = v
+	}
+	if v, ok := ReadString(data, "https_ca"); ok {
+		cfg.HTTPSCA = v
+	}
+	if v, ok := ReadInt(data, "https_port"); ok {
+		cfg.HTTPSPort = v
+	}
+	return *cfg
+}
+
+// BackupDaemonConfigSchema holds a minimal set of parameters required to deploy a Backup Daemon
+var BackupDaemonConfigSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"binary": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"workdir": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"mongo_uri": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+		},
+		"encryption_key": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			Sensitive:    true,
+			ValidateFunc: ValidateEncryptionKey,
+		},
+		"port": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  8080,
+		},
+		"central_url": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"overrides": {
+			Type:     schema.TypeMap,
+			Optional: true,
+		},
+		"head_directory": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"machine": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		},
+		"num_workers": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  50,
+		},
+		"retain_encryption_key": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"https_pem_key": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "PEM encoded certificate and private key; if specified, Ops Manager only serves HTTPS",
+		},
+		"https_pem_key_password": {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Sensitive: true,
+		},
+		"https_ca": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "PEM encoded certificate authority which signed the https_pem_key certificate",
+		},
+		"https_port": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  8443,
+		},
+	},
+}
+
+// OpsManagerConfig returns the configuration used to install Ops Manager (which runs the Backup Daemon) on the daemon's host;
+// the daemon's properties are written to conf-mms.properties along with the overrides, which take precedence
+func (cfg BackupDaemonConfig) OpsManagerConfig() OpsManagerConfig {
+	overrides := make(map[string]interface{})
+	for prop, val := range cfg.DaemonProperties() {
+		overrides[prop] = val
+	}
+	for prop, val := range cfg.Overrides {
+		overrides[prop] = val
+	}
+
+	return OpsManagerConfig{
+		Binary:              cfg.Binary,
+		WorkDir:             cfg.WorkDir,
+		MongoURI:            cfg.MongoURI,
+		EncryptionKey:       cfg.EncryptionKey,
+		Port:                cfg.Port,
+		CentralURL:          cfg.CentralURL,
+		Overrides:           overrides,
+		RetainEncryptionKey: cfg.RetainEncryptionKey,
+		HTTPSPEMKey:         cfg.HTTPSPEMKey,
+		HTTPSPEMKeyPassword: cfg.HTTPSPEMKeyPassword,
+		HTTPSCA:             cfg.HTTPSCA,
+		HTTPSPort:           cfg.HTTPSPort,
+	}
+}
+
+// DaemonProperties returns the backup.daemon.* properties which configure the daemon's head directory and worker count
+func (cfg BackupDaemonConfig) DaemonProperties() map[string]string {
+	return map[string]string{
+		"backup.daemon.headDirectory": cfg.HeadDirectory,
+		"backup.daemon.numWorkers":    strconv.Itoa(cfg.NumWorkers),
+	}
+}
+
+// NormalizeHeadDirectory returns the configured head directory if it points to the same path as the one reported by Ops Manager
+// (which may differ in trailing slashes), or the reported directory otherwise
+func NormalizeHeadDirectory(configured string, reported string) string {
+	if filepath.Clean(configured) == filepath.Clean(reported) {
+		return configured
+	}
+	return reported
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestReadBackupDaemonConfig_unit(t *testing.T) {
+	cfg := ReadBackupDaemonConfig([]interface{}{map[string]interface{}{
+		"binary":         "https://downloads.example.com/mongodb-mms.tar.gz",
+		"workdir":        "/opt/mongodb/mms",
+		"head_directory": "/data/head",
+		"num_workers":    10,
+		"overrides":      map[string]interface{}{"backup.daemon.numWorkers": "20", "mms.fromEmailAddr": "mms@example.com"},
+	}})
+	if cfg.HeadDirectory != "/data/head" || cfg.NumWorkers != 10 {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	// the daemon's properties are written along with the overrides, which take precedence
+	overrides := cfg.OpsManagerConfig().Overrides
+	if v := overrides["backup.daemon.headDirectory"]; v != "/data/head" {
+		t.Errorf("unexpected head directory: %v", v)
+	}
+	if v := overrides["backup.daemon.numWorkers"]; v != "20" {
+		t.Errorf("unexpected number of workers: %v", v)
+	}
+	if v := overrides["mms.fromEmailAddr"]; v != "mms@example.com" {
+		t.Errorf("unexpected override: %v", v)
+	}
+	if len(cfg.Overrides) != 2 {
+		t.Errorf("the daemon's overrides were modified: %v", cfg.Overrides)
+	}
+}
+
+func TestBackupDaemonHTTPSConfig_unit(t *testing.T) {
+	cfg := ReadBackupDaemonConfig([]interface{}{map[string]interface{}{
+		"https_pem_key": "-----BEGIN CERTIFICATE-----",
+		"https_ca":      "-----BEGIN CERTIFICATE-----",
+		"https_port":    9443,
+	}})
+
+	// the daemon's host serves HTTPS like the other application servers
+	omConfig := cfg.OpsManagerConfig()
+	if !omConfig.IsHTTPS() || omConfig.HTTPSCA == "" || omConfig.ServingPort() != 9443 {
+		t.Errorf("the HTTPS settings were not passed to Ops Manager: %+v", omConfig)
+	}
+}
+
+func TestNormalizeHeadDirectory_unit(t *testing.T) {
+	if v := NormalizeHeadDirectory("/data/head", "/data/head/"); v != "/data/head" {
+		t.Errorf("unexpected head directory: %s", v)
+	}
+	if v := NormalizeHeadDirectory("/data/head", "/data/other/"); v != "/data/other/" {
+		t.Errorf("unexpected head directory: %s", v)
+	}
+}
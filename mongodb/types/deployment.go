@@ -0,0 +1,249 @@
+package types
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// DeploymentProcess holder for a single mongod process, managed through the automation config
+type DeploymentProcess struct {
+	Name                        string  `json:"name,omitempty"`
+	Hostname                    string  `json:"hostname,omitempty"`
+	Port                        int     `json:"port,omitempty"`
+	Version                     string  `json:"version,omitempty"`
+	FeatureCompatibilityVersion string  `json:"feature_compatibility_version,omitempty"`
+	DBPath                      string  `json:"db_path,omitempty"`
+	LogPath                     string  `json:"log_path,omitempty"`
+	ReplicaSet                  string  `json:"replica_set,omitempty"`
+	Priority                    float64 `json:"priority,omitempty"`
+	Votes                       int     `json:"votes,omitempty"`
+	ArbiterOnly                 bool    `json:"arbiter_only,omitempty"`
+	Hidden                      bool    `json:"hidden,omitempty"`
+}
+
+// DeploymentAuth holder for the deployment's authentication settings
+type DeploymentAuth struct {
+	Mechanisms   []string `json:"mechanisms,omitempty"`
+	AutoUser     string   `json:"auto_user,omitempty"`
+	AutoPassword string   `json:"auto_password,omitempty"`
+	Key          string   `json:"key,omitempty"`
+	Keyfile      string   `json:"keyfile,omitempty"`
+}
+
+// DeploymentSchema constructs a terraform schema map representing a deployment defined in a project's automation config
+func DeploymentSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"project_id": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"process": {
+			Type:     schema.TypeList,
+			Required: true,
+			MinItems: 1,
+			Elem:     DeploymentProcessSchema,
+		},
+		"auth": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem:     DeploymentAuthSchema,
+		},
+		"goal_version": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+	}
+}
+
+// DeploymentProcessSchema holds the parameters of a single mongod process and, optionally, its replica set membership
+var DeploymentProcessSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"hostname": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"port": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  27017,
+		},
+		"version": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"feature_compatibility_version": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"db_path": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"log_path": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"replica_set": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"priority": {
+			Type:     schema.TypeFloat,
+			Optional: true,
+			Default:  1,
+		},
+		"votes": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  1,
+		},
+		"arbiter_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"hidden": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+// DeploymentAuthSchema holds the deployment's authentication settings
+var DeploymentAuthSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"mechanisms": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"auto_user": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "mms-automation",
+		},
+		"auto_password": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+		},
+		"key": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+		},
+		"keyfile": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "/var/lib/mongodb-mms-automation/keyfile",
+		},
+	},
+}
+
+// ReadDeploymentProcesses parses a list of DeploymentProcessSchema resources as a slice of DeploymentProcess types
+func ReadDeploymentProcesses(list []interface{}) []DeploymentProcess {
+	processes := make([]DeploymentProcess, 0, len(list))
+	for _, item := range list {
+		process := DeploymentProcess{}
+		data := item.(map[string]interface{})
+		if v, ok := ReadString(data, "name"); ok {
+			process.Name = v
+		}
+		if v, ok := ReadString(data, "hostname"); ok {
+			process.Hostname = v
+		}
+		if v, ok := ReadInt(data, "port"); ok {
+			process.Port = v
+		}
+		if v, ok := ReadString(data, "version"); ok {
+			process.Version = v
+		}
+		if v, ok := ReadString(data, "feature_compatibility_version"); ok {
+			process.FeatureCompatibilityVersion = v
+		}
+		if v, ok := ReadString(data, "db_path"); ok {
+			process.DBPath = v
+		}
+		if v, ok := ReadString(data, "log_path"); ok {
+			process.LogPath = v
+		}
+		if v, ok := ReadString(data, "replica_set"); ok {
+			process.ReplicaSet = v
+		}
+		if v, ok := ReadFloat(data, "priority"); ok {
+			process.Priority = v
+		}
+		if v, ok := ReadInt(data, "votes"); ok {
+			process.Votes = v
+		}
+		if v, ok := ReadBool(data, "arbiter_only"); ok {
+			process.ArbiterOnly = v
+		}
+		if v, ok := ReadBool(data, "hidden"); ok {
+			process.Hidden = v
+		}
+		processes = append(processes, process)
+	}
+	return processes
+}
+
+// FlattenDeploymentProcesses converts a slice of DeploymentProcess types into a list of DeploymentProcessSchema resources
+func FlattenDeploymentProcesses(processes []DeploymentProcess) []interface{} {
+	list := make([]interface{}, 0, len(processes))
+	for _, process := range processes {
+		list = append(list, map[string]interface{}{
+			"name":                          process.Name,
+			"hostname":                      process.Hostname,
+			"port":                          process.Port,
+			"version":                       process.Version,
+			"feature_compatibility_version": process.FeatureCompatibilityVersion,
+			"db_path":                       process.DBPath,
+			"log_path":                      process.LogPath,
+			"replica_set":                   process.ReplicaSet,
+			"priority":                      process.Priority,
+			"votes":                         process.Votes,
+			"arbiter_only":                  process.ArbiterOnly,
+			"hidden":                        process.Hidden,
+		})
+	}
+	return list
+}
+
+// ReadDeploymentAuth parses a singleton list of DeploymentAuthSchema resources as a DeploymentAuth type
+func ReadDeploymentAuth(list []interface{}) DeploymentAuth {
+	auth := &DeploymentAuth{}
+	data := list[0].(map[string]interface{})
+	if v, ok := ReadStringList(data, "mechanisms"); ok {
+		auth.Mechanisms = v
+	}
+	if v, ok := ReadString(data, "auto_user"); ok {
+		auth.AutoUser = v
+	}
+	if v, ok := ReadString(data, "auto_password"); ok {
+		auth.AutoPassword = v
+	}
+	if v, ok := ReadString(data, "key"); ok {
+		auth.Key = v
+	}
+	if v, ok := ReadString(data, "keyfile"); ok {
+		auth.Keyfile = v
+	}
+	return *auth
+}
+
+// FlattenDeploymentAuth converts a DeploymentAuth type into a singleton list of DeploymentAuthSchema resources
+func FlattenDeploymentAuth(auth DeploymentAuth) []interface{} {
+	return []interface{}{map[string]interface{}{
+		"mechanisms":    auth.Mechanisms,
+		"auto_user":     auth.AutoUser,
+		"auto_password": auth.AutoPassword,
+		"key":           auth.Key,
+		"keyfile":       auth.Keyfile,
+	}}
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// EncryptionKeyLength the length, in bytes, of the key used by Ops Manager to encrypt sensitive data in its application database
+const EncryptionKeyLength = 24
+
+// GenerateEncryptionKey generates a random encryption key, encoded as base64
+func GenerateEncryptionKey() (string, error) {
+	key := make([]byte, EncryptionKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("could not generate an encryption key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// DecodeEncryptionKey decodes a base64 encoded encryption key, checking its length
+func DecodeEncryptionKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("the encryption key must be base64 encoded: %v", err)
+	}
+	if len(key) != EncryptionKeyLength {
+		return nil, fmt.Errorf("the encryption key must be %d bytes long, got %d", EncryptionKeyLength, len(key))
+	}
+	return key, nil
+}
+
+// EncryptionKeyChecksum returns the hex encoded SHA-256 checksum of a base64 encoded encryption key,
+// as printed by sha256sum for the key file; used to compare keys without transferring them
+func EncryptionKeyChecksum(encoded string) (string, error) {
+	key, err := DecodeEncryptionKey(encoded)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(key)), nil
+}
+
+// ValidateEncryptionKey schema.SchemaValidateFunc which checks that a key can be decoded by DecodeEncryptionKey
+func ValidateEncryptionKey(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := DecodeEncryptionKey(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q: %v", k, err))
+	}
+	return
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestValidateEncryptionKey_unit(t *testing.T) {
+	key, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, errs := ValidateEncryptionKey(key, "encryption_key"); len(errs) != 0 {
+		t.Errorf("expected a generated key to be valid, got: %v", errs)
+	}
+
+	for _, invalid := range []string{"", "not base64!", "c2hvcnQ=", "abcdefghijklmnopqrstuvwxyz'\"abcdefghijkl"} {
+		if _, errs := ValidateEncryptionKey(invalid, "encryption_key"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
+
+func TestEncryptionKeyChecksum_unit(t *testing.T) {
+	// printf 'abcdefghijklmnopqrstuvwx' | sha256sum
+	checksum, err := EncryptionKeyChecksum("YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checksum != "93b0cabf8668e0c534c52a568957499e12a284f59d97dc9b2725ef836804875b" {
+		t.Errorf("unexpected checksum: %s", checksum)
+	}
+
+	if _, err := EncryptionKeyChecksum("c2hvcnQ="); err == nil {
+		t.Error("expected an invalid key to be rejected")
+	}
+}
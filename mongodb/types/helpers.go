@@ -4,13 +4,15 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
 )
 
-// StrictUnionOrFatal convenience method which throws a fatal error if duplicate keys are found while merging multiple terraform schema maps
-func StrictUnionOrFatal(maps ...map[string]*schema.Schema) map[string]*schema.Schema {
+// MustStrictUnion convenience method which panics if duplicate keys are found while merging multiple terraform schema maps;
+// only to be used when building the provider's schema at startup, where duplicate keys are a programming error
+func MustStrictUnion(maps ...map[string]*schema.Schema) map[string]*schema.Schema {
 	union, err := StrictUnion(maps...)
-	util.PanicOnNonNilErr(err)
+	if err != nil {
+		panic(fmt.Sprintf("invalid schema: %v", err))
+	}
 	return union
 }
 
@@ -41,12 +43,14 @@ func mapUnion(collector *map[string]*schema.Schema, data map[string]*schema.Sche
 	return nil
 }
 
-// NewSchemaMap build a new schema map based on the passed configuration
+// NewSchemaMap build a new schema map based on the passed configuration;
+// panics if duplicate keys are found, since resource schemas are only built at startup
 func NewSchemaMap(config ...func() map[string]*schema.Schema) map[string]*schema.Schema {
 	data := make(map[string]*schema.Schema)
 	for _, configPart := range config {
-		err := mapUnion(&data, configPart())
-		util.PanicOnNonNilErr(err)
+		if err := mapUnion(&data, configPart()); err != nil {
+			panic(fmt.Sprintf("invalid schema: %v", err))
+		}
 	}
 
 	return data
@@ -91,3 +95,29 @@ func ReadStringMap(input map[string]interface{}, key string) (map[string]interfa
 	}
 	return make(map[string]interface{}), false
 }
+
+// ReadStringList reads all values in a list (or set) of strings
+func ReadStringList(input map[string]interface{}, key string) ([]string, bool) {
+	var v []interface{}
+	switch value := input[key].(type) {
+	case []string:
+		return value, true
+	case []interface{}:
+		v = value
+	case *schema.Set:
+		v = value.List()
+	default:
+		return nil, false
+	}
+
+	list := make([]string, 0, len(v))
+	for _, item := range v {
+		list = append(list, item.(string))
+	}
+	return list, true
+}
+
+// SuppressDiffAfterCreate ignores changes to attributes which are only used when the resource is created
+func SuppressDiffAfterCreate(k, old, new string, d *schema.ResourceData) bool {
+	return d.Id() != ""
+}
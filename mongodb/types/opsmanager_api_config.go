@@ -0,0 +1,105 @@
+package types
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// OpsManagerAPIConfig holder for the parameters used to connect to the Ops Manager API
+type OpsManagerAPIConfig struct {
+	BaseURL    string `json:"base_url,omitempty"`
+	Username   string `json:"username,omitempty"`
+	APIKey     string `json:"api_key,omitempty"`
+	PublicKey  string `json:"public_key,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"`
+	CACert     string `json:"ca_cert,omitempty"`
+}
+
+// Credentials returns the programmatic API key pair, if specified, or otherwise the username and its API key
+func (cfg OpsManagerAPIConfig) Credentials() (string, string) {
+	if cfg.PublicKey != "" {
+		return cfg.PublicKey, cfg.PrivateKey
+	}
+	return cfg.Username, cfg.APIKey
+}
+
+// ReadOpsManagerAPIConfig parses a singleton list of OpsManagerAPIConfigSchema resources as a OpsManagerAPIConfig type
+func ReadOpsManagerAPIConfig(list []interface{}) OpsManagerAPIConfig {
+	// read the connection params
+	cfg := &OpsManagerAPIConfig{}
+	data := list[0].(map[string]interface{})
+	if v, ok := ReadString(data, "base_url"); ok {
+		cfg.BaseURL = v
+	}
+	if v, ok := ReadString(data, "username"); ok {
+		cfg.Username = v
+	}
+	if v, ok := ReadString(data, "api_key"); ok {
+		cfg.APIKey = v
+	}
+	if v, ok := ReadString(data, "public_key"); ok {
+		cfg.PublicKey = v
+	}
+	if v, ok := ReadString(data, "private_key"); ok {
+		cfg.PrivateKey = v
+	}
+	if v, ok := ReadString(data, "ca_cert"); ok {
+		cfg.CACert = v
+	}
+	return *cfg
+}
+
+// OpsManagerAPISchema constructs a terraform schema map representing the provider-level Ops Manager API connection params
+func OpsManagerAPISchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"opsmanager_api": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem:        OpsManagerAPIConfigSchema,
+			Description: "Default Ops Manager API connection params, used by resources which do not define their own",
+		},
+	}
+}
+
+// ReadOpsManagerAPISchema reads the provider-level Ops Manager API configuration, if it was specified
+func ReadOpsManagerAPISchema(data *schema.ResourceData) (OpsManagerAPIConfig, bool) {
+	list, ok := data.GetOk("opsmanager_api")
+	if !ok {
+		return OpsManagerAPIConfig{}, false
+	}
+	return ReadOpsManagerAPIConfig(list.([]interface{})), true
+}
+
+// OpsManagerAPIConfigSchema holds the parameters required to connect to the Ops Manager API;
+// requests are authenticated with either a username and its API key, or with a programmatic API key pair
+var OpsManagerAPIConfigSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"base_url": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"username": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"api_key": {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Sensitive: true,
+		},
+		"public_key": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"private_key": {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Sensitive: true,
+		},
+		"ca_cert": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "PEM encoded certificate authority used to validate Ops Manager's certificate",
+		},
+	},
+}
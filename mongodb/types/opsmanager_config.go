@@ -1,6 +1,7 @@
 package types
 
 import (
+	"fmt"
 	"path"
 	"reflect"
 	"strings"
@@ -10,19 +11,29 @@ import (
 
 // OpsManagerConfig holder for Ops Manager config
 type OpsManagerConfig struct {
-	Binary              string                 `json:"binary,omitempty"`
-	WorkDir             string                 `json:"workdir,omitempty"`
-	MongoURI            string                 `json:"mongo_uri,omitempty" opsmanager:"mongo.mongoUri"`
-	EncryptionKey       string                 `json:"encryption_key,omitempty"` // /etc/mongodb-mms/gen.key
-	Port                int                    `json:"port,omitempty" opsmanager:"BASE_PORT"`
-	CentralURL          string                 `json:"central_url,omitempty" opsmanager:"mms.centralUrl"`
-	Overrides           map[string]interface{} `json:"overrides,omitempty"`
-	RegisterGlobalOwner bool                   `json:"register_global_owner,omitempty"`
-	GlobalOwnerUsername string                 `json:"global_owner_username,omitempty"`
-	GlobalOwnerPassword string                 `json:"global_owner_password,omitempty"`
-	ExternalPort        int                    `json:"external_port,omitempty"`
-	MMSGroupID          string                 `json:"mms_group_id,omitempty" automation:"mmsGroupId"`
-	MMSAgentAPIKey      string                 `json:"mms_agent_api_key,omitempty" automation:"mmsApiKey"`
+	Binary                string                 `json:"binary,omitempty"`
+	WorkDir               string                 `json:"workdir,omitempty"`
+	MongoURI              string                 `json:"mongo_uri,omitempty" opsmanager:"mongo.mongoUri"`
+	EncryptionKey         string                 `json:"encryption_key,omitempty"` // /etc/mongodb-mms/gen.key
+	Port                  int                    `json:"port,omitempty" opsmanager:"BASE_PORT"`
+	CentralURL            string                 `json:"central_url,omitempty" opsmanager:"mms.centralUrl"`
+	Overrides             map[string]interface{} `json:"overrides,omitempty"`
+	RegisterGlobalOwner   bool                   `json:"register_global_owner,omitempty"`
+	GlobalOwnerUsername   string                 `json:"global_owner_username,omitempty"`
+	GlobalOwnerPassword   string                 `json:"global_owner_password,omitempty"`
+	GlobalOwnerAccessList []string               `json:"global_owner_access_list,omitempty"`
+	GlobalOwnerAPIKey     string                 `json:"global_owner_api_key,omitempty"`
+	InitialProjectName    string                 `json:"initial_project_name,omitempty"`
+	ExternalPort          int                    `json:"external_port,omitempty"`
+	APIURL                string                 `json:"api_url,omitempty"`
+	APIURLComputed        bool                   `json:"api_url_computed,omitempty"`
+	MMSGroupID            string                 `json:"mms_group_id,omitempty" automation:"mmsGroupId"`
+	MMSAgentAPIKey        string                 `json:"mms_agent_api_key,omitempty" automation:"mmsApiKey"`
+	RetainEncryptionKey   bool                   `json:"retain_encryption_key,omitempty"`
+	HTTPSPEMKey           string                 `json:"https_pem_key,omitempty" opsmanager:"mms.https.PEMKeyFile"`
+	HTTPSPEMKeyPassword   string                 `json:"https_pem_key_password,omitempty" opsmanager:"mms.https.PEMKeyFilePassword"`
+	HTTPSCA               string                 `json:"https_ca,omitempty" opsmanager:"mms.https.CAFile"`
+	HTTPSPort             int                    `json:"https_port,omitempty" opsmanager:"BASE_SSL_PORT"`
 }
 
 // ReadOpsManagerConfig parses a singleton list of OpsManagerConfigSchema resources as a OpsManagerConfig type
@@ -60,15 +71,45 @@ func ReadOpsManagerConfig(list []interface{}) OpsManagerConfig {
 	if v, ok := ReadString(data, "global_owner_password"); ok {
 		cfg.GlobalOwnerPassword = v
 	}
+	if v, ok := ReadStringList(data, "global_owner_access_list"); ok {
+		cfg.GlobalOwnerAccessList = v
+	}
+	if v, ok := ReadString(data, "global_owner_api_key"); ok {
+		cfg.GlobalOwnerAPIKey = v
+	}
+	if v, ok := ReadString(data, "initial_project_name"); ok {
+		cfg.InitialProjectName = v
+	}
 	if v, ok := ReadInt(data, "external_port"); ok {
 		cfg.ExternalPort = v
 	}
+	if v, ok := ReadString(data, "api_url"); ok {
+		cfg.APIURL = v
+	}
+	if v, ok := ReadBool(data, "api_url_computed"); ok {
+		cfg.APIURLComputed = v
+	}
 	if v, ok := ReadString(data, "mms_group_id"); ok {
 		cfg.MMSGroupID = v
 	}
 	if v, ok := ReadString(data, "mms_agent_api_key"); ok {
 		cfg.MMSAgentAPIKey = v
 	}
+	if v, ok := ReadBool(data, "retain_encryption_key"); ok {
+		cfg.RetainEncryptionKey = v
+	}
+	if v, ok := ReadString(data, "https_pem_key"); ok {
+		cfg.HTTPSPEMKey = v
+	}
+	if v, ok := ReadString(data, "https_pem_key_password"); ok {
+		cfg.HTTPSPEMKeyPassword = v
+	}
+	if v, ok := ReadString(data, "https_ca"); ok {
+		cfg.HTTPSCA = v
+	}
+	if v, ok := ReadInt(data, "https_port"); ok {
+		cfg.HTTPSPort = v
+	}
 	return *cfg
 }
 
@@ -78,18 +119,26 @@ var OpsManagerConfigSchema = &schema.Resource{
 		"binary": {
 			Type:     schema.TypeString,
 			Required: true,
+			ForceNew: true,
 		},
 		"workdir": {
 			Type:     schema.TypeString,
 			Required: true,
+			ForceNew: true,
 		},
 		"mongo_uri": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
 		},
 		"encryption_key": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			Sensitive:    true,
+			ValidateFunc: ValidateEncryptionKey,
+			Description:  "base64 encoded 24 byte key; generated if not specified",
 		},
 		"port": {
 			Type:     schema.TypeInt,
@@ -119,10 +168,39 @@ var OpsManagerConfigSchema = &schema.Resource{
 			Type:     schema.TypeString,
 			Optional: true,
 		},
+		"global_owner_access_list": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "CIDR blocks from which the global owner can call the API; defaults to all IPv4 addresses",
+		},
+		"global_owner_api_key": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		// the project is only created along with the global owner; renaming it afterwards has no effect
+		"initial_project_name": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			DiffSuppressFunc: SuppressDiffAfterCreate,
+			Description:      "name of the project created along with the global owner; only used when creating the resource",
+		},
 		"external_port": {
 			Type:     schema.TypeInt,
 			Optional: true,
 		},
+		"api_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "URL used to call the Ops Manager API after installing it; defaults to central_url, or the first host's name",
+		},
+		// true if api_url was not specified, and is recomputed whenever the values it is derived from change
+		"api_url_computed": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
 		"mms_group_id": {
 			Type:     schema.TypeString,
 			Computed: true,
@@ -133,29 +211,177 @@ var OpsManagerConfigSchema = &schema.Resource{
 			Computed: true,
 			ForceNew: true,
 		},
+		"retain_encryption_key": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"https_pem_key": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "PEM encoded certificate and private key; if specified, Ops Manager only serves HTTPS",
+		},
+		"https_pem_key_password": {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Sensitive: true,
+		},
+		"https_ca": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "PEM encoded certificate authority which signed the https_pem_key certificate",
+		},
+		"https_port": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  8443,
+		},
 	},
 }
 
+// IsArchive returns true if Ops Manager is installed from a tar.gz archive, rather than from a package
+func (cfg OpsManagerConfig) IsArchive() bool {
+	return strings.HasSuffix(cfg.Binary, ".tar.gz") || strings.HasSuffix(cfg.Binary, ".tgz")
+}
+
 // ConfigOverrideFilename returns the path to the config override filename
 func (cfg OpsManagerConfig) ConfigOverrideFilename() string {
-	// if Ops Manager was installed from a tar.gz, use the working directory
-	baseDir := "/opt/mongodb"
-	if strings.HasSuffix(cfg.Binary, "tar.gz") || strings.HasSuffix(cfg.Binary, "tgz") {
-		baseDir = cfg.WorkDir
-	}
-
-	return path.Join(baseDir, "mms", "conf", "conf-mms.properties")
+	return path.Join(cfg.confDir(), "conf-mms.properties")
 }
 
 // SysConfigFilename returns the path to the sysconfig filename
 func (cfg OpsManagerConfig) SysConfigFilename() string {
-	// if Ops Manager was installed from a tar.gz, use the working directory
-	baseDir := "/opt/mongodb"
-	if strings.HasSuffix(cfg.Binary, "tar.gz") || strings.HasSuffix(cfg.Binary, "tgz") {
-		baseDir = cfg.WorkDir
+	return path.Join(cfg.confDir(), "mms.conf")
+}
+
+// confDir returns the directory which holds Ops Manager's configuration files
+func (cfg OpsManagerConfig) confDir() string {
+	// if Ops Manager was installed from a tar.gz, its files are unpacked in the working directory
+	if cfg.IsArchive() {
+		return path.Join(cfg.WorkDir, "conf")
+	}
+
+	return path.Join("/opt/mongodb", "mms", "conf")
+}
+
+// HealthCheckURL returns the URL which reports whether Ops Manager is ready to serve requests, from the host it is installed on
+func (cfg OpsManagerConfig) HealthCheckURL() string {
+	return fmt.Sprintf("%s://localhost:%d/monitor/health", cfg.Scheme(), cfg.ServingPort())
+}
+
+// StartupLogFilename returns the path to the log written by Ops Manager while starting up
+func (cfg OpsManagerConfig) StartupLogFilename() string {
+	// if Ops Manager was installed from a tar.gz, its logs are written in the working directory
+	if cfg.IsArchive() {
+		return path.Join(cfg.WorkDir, "logs", "mms0-startup.log")
+	}
+
+	return path.Join("/opt/mongodb", "mms", "logs", "mms0-startup.log")
+}
+
+// EncryptionKeyFilename returns the path to the Ops Manager encryption key
+func (cfg OpsManagerConfig) EncryptionKeyFilename() string {
+	return "/etc/mongodb-mms/gen.key"
+}
+
+// IsHTTPS returns true if Ops Manager serves HTTPS
+func (cfg OpsManagerConfig) IsHTTPS() bool {
+	return cfg.HTTPSPEMKey != ""
+}
+
+// ServingPort returns the port on which Ops Manager serves requests, depending on whether HTTPS is enabled
+func (cfg OpsManagerConfig) ServingPort() int {
+	if cfg.IsHTTPS() {
+		return cfg.HTTPSPort
+	}
+	return cfg.Port
+}
+
+// Scheme returns the URL scheme on which Ops Manager serves requests
+func (cfg OpsManagerConfig) Scheme() string {
+	if cfg.IsHTTPS() {
+		return "https"
+	}
+	return "http"
+}
+
+// BootstrapURL returns the URL used to call the Ops Manager API after installing it on the specified host:
+// api_url if explicitly set, otherwise central_url, falling back to the host's name
+func (cfg OpsManagerConfig) BootstrapURL(hostname string) string {
+	if cfg.APIURL != "" && !cfg.APIURLComputed {
+		return cfg.APIURL
+	}
+	if cfg.CentralURL != "" {
+		return cfg.CentralURL
+	}
+
+	// without an explicit URL, assume that Ops Manager can be reached on the SSH host name
+	port := cfg.ExternalPort
+	if port == 0 {
+		port = cfg.ServingPort()
+	}
+	return fmt.Sprintf("%s://%s:%d", cfg.Scheme(), hostname, port)
+}
+
+// ResolveAPIURL sets api_url to the URL returned by BootstrapURL, marking it as computed if it was not explicitly set
+func (cfg *OpsManagerConfig) ResolveAPIURL(hostname string) {
+	if cfg.APIURL == "" {
+		cfg.APIURLComputed = true
+	}
+	cfg.APIURL = cfg.BootstrapURL(hostname)
+}
+
+// HTTPSPEMKeyFilename returns the path to the PEM file used for serving HTTPS
+func (cfg OpsManagerConfig) HTTPSPEMKeyFilename() string {
+	return "/etc/mongodb-mms/https.pem"
+}
+
+// HTTPSCAFilename returns the path to the certificate authority which signed the HTTPS certificate
+func (cfg OpsManagerConfig) HTTPSCAFilename() string {
+	return "/etc/mongodb-mms/https-ca.pem"
+}
+
+// ServiceCommand returns a command which performs the specified action (start, stop, restart) on the Ops Manager service,
+// using systemd if it manages the remote host, the init.d script otherwise, or the bundled script for archive installs
+func (cfg OpsManagerConfig) ServiceCommand(action string) string {
+	// archive installs are managed through the bundled script, run as the service user
+	if cfg.IsArchive() {
+		return fmt.Sprintf("su -s /bin/sh mongodb-mms -c '%s %s'", path.Join(cfg.WorkDir, "bin", "mongodb-mms"), action)
+	}
+
+	return fmt.Sprintf("bash -c \"if [ -d /run/systemd/system ]; then systemctl %[1]s mongodb-mms; else /etc/init.d/mongodb-mms %[1]s; fi\"", action)
+}
+
+// BinaryFilename returns the path to which the Ops Manager package or archive is uploaded
+func (cfg OpsManagerConfig) BinaryFilename() string {
+	return path.Join(cfg.WorkDir, path.Base(cfg.Binary))
+}
+
+// RemoveFilesCommand returns a command which removes the uploaded package or archive and, for archive installs,
+// the files unpacked from it; the working directory itself is kept, since it may be shared with other processes
+func (cfg OpsManagerConfig) RemoveFilesCommand() string {
+	if !cfg.IsArchive() {
+		return fmt.Sprintf("rm -f %s", cfg.BinaryFilename())
+	}
+
+	// the archive was unpacked without its top-level directory; directories are only removed once empty,
+	// as they may also hold files which do not belong to Ops Manager
+	return fmt.Sprintf(`bash -c "if [ -f %[1]s ]; then cd %[2]s && tar -tzf %[1]s | cut -d/ -f2- | grep -v '^$' | sort -r | `+
+		`while read -r entry; do if [ -d \"\$entry\" ]; then rmdir --ignore-fail-on-non-empty \"\$entry\"; else rm -f \"\$entry\"; fi; done; fi; rm -f %[1]s"`,
+		cfg.BinaryFilename(), cfg.WorkDir)
+}
+
+// UninstallCommand returns a command which removes the Ops Manager package, if it is installed
+func (cfg OpsManagerConfig) UninstallCommand() (string, bool) {
+	if strings.HasSuffix(cfg.Binary, ".deb") {
+		return "bash -c \"if dpkg -s mongodb-mms >/dev/null 2>&1; then dpkg -r mongodb-mms; fi\"", true
+	}
+	if strings.HasSuffix(cfg.Binary, ".rpm") {
+		return "bash -c \"if rpm -q mongodb-mms >/dev/null 2>&1; then rpm -e mongodb-mms; fi\"", true
 	}
 
-	return path.Join(baseDir, "mms", "conf", "mms.conf")
+	return "", false
 }
 
 // GetOpsManagerTag returns the specified opsmanager tag
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestSuppressInitialProjectNameAfterCreate_unit(t *testing.T) {
+	suppress := OpsManagerConfigSchema.Schema["initial_project_name"].DiffSuppressFunc
+	if suppress == nil {
+		t.Fatal("expected changes to initial_project_name to be suppressed")
+	}
+
+	data := schema.TestResourceDataRaw(t, OpsManagerConfigSchema.Schema, map[string]interface{}{})
+	if suppress("initial_project_name", "", "project", data) {
+		t.Error("the project name should be set when creating the resource")
+	}
+
+	data.SetId("opsmanager")
+	if !suppress("initial_project_name", "project", "renamed", data) {
+		t.Error("renaming the project should not change an existing resource")
+	}
+}
+
+func TestResolveAPIURL_unit(t *testing.T) {
+	// api_url defaults to central_url, and follows its changes
+	cfg := OpsManagerConfig{CentralURL: "http://opsmanager.example.com:8080"}
+	cfg.ResolveAPIURL("host1")
+	if cfg.APIURL != "http://opsmanager.example.com:8080" || !cfg.APIURLComputed {
+		t.Errorf("unexpected api_url: %s (computed: %v)", cfg.APIURL, cfg.APIURLComputed)
+	}
+	cfg.CentralURL = "https://opsmanager.example.com:8443"
+	cfg.ResolveAPIURL("host1")
+	if cfg.APIURL != "https://opsmanager.example.com:8443" {
+		t.Errorf("expected api_url to follow central_url, got: %s", cfg.APIURL)
+	}
+
+	// without central_url, api_url follows the host and port
+	cfg = OpsManagerConfig{Port: 8080}
+	cfg.ResolveAPIURL("host1")
+	cfg.Port = 9090
+	cfg.ResolveAPIURL("host1")
+	if cfg.APIURL != "http://host1:9090" {
+		t.Errorf("expected api_url to follow the port, got: %s", cfg.APIURL)
+	}
+
+	// an explicit api_url is kept
+	cfg = OpsManagerConfig{APIURL: "http://127.0.0.1:32768", CentralURL: "http://opsmanager.example.com:8080"}
+	cfg.ResolveAPIURL("host1")
+	cfg.CentralURL = "http://other.example.com:8080"
+	cfg.ResolveAPIURL("host1")
+	if cfg.APIURL != "http://127.0.0.1:32768" || cfg.APIURLComputed {
+		t.Errorf("expected the explicit api_url to be kept, got: %s (computed: %v)", cfg.APIURL, cfg.APIURLComputed)
+	}
+}
+
+func TestRemoveFilesCommand_unit(t *testing.T) {
+	// the working directory may be shared with other processes, and must not be removed
+	pkg := OpsManagerConfig{WorkDir: "/opt/mongodb", Binary: "http://localhost:9000/mongodb-mms_4.1.8-1_x86_64.deb"}
+	if cmd := pkg.RemoveFilesCommand(); cmd != "rm -f /opt/mongodb/mongodb-mms_4.1.8-1_x86_64.deb" {
+		t.Errorf("unexpected command: %s", cmd)
+	}
+
+	archive := OpsManagerConfig{WorkDir: "/opt/mongodb", Binary: "http://localhost:9000/mongodb-mms-4.1.8.x86_64.tar.gz"}
+	cmd := archive.RemoveFilesCommand()
+	if !strings.Contains(cmd, "tar -tzf /opt/mongodb/mongodb-mms-4.1.8.x86_64.tar.gz") || strings.Contains(cmd, "rm -rf") {
+		t.Errorf("expected only the archive's files to be removed: %s", cmd)
+	}
+}
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// OrganizationSchema constructs a terraform schema map representing an Ops Manager organization
+func OrganizationSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"org_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// ProjectSchema constructs a terraform schema map representing an Ops Manager project
+func ProjectSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"org_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		},
+		"project_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"agent_api_key": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+	}
+}
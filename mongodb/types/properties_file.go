@@ -3,11 +3,10 @@ package types
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"log"
 	"strings"
 
-	"github.com/mongodb-labs/terraform-provider-mongodb/mongodb/util"
-
 	"github.com/magiconair/properties"
 )
 
@@ -17,13 +16,15 @@ type PropertiesFile struct {
 }
 
 // NewPropertiesFile create a new wrapper for Ops Manager config files
-func NewPropertiesFile(data string) *PropertiesFile {
+func NewPropertiesFile(data string) (*PropertiesFile, error) {
 	loader := &properties.Loader{DisableExpansion: true, Encoding: properties.UTF8}
 	p, err := loader.LoadBytes([]byte(data))
-	util.PanicOnNonNilErr(err)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the properties file: %v", err)
+	}
 
 	log.Print("[DEBUG] Loaded properties file...")
-	return &PropertiesFile{props: p}
+	return &PropertiesFile{props: p}, nil
 }
 
 // SetPropertyValue sets/updates a property key, value pair
@@ -33,6 +34,16 @@ func (cfg *PropertiesFile) SetPropertyValue(key string, value string) {
 	}
 }
 
+// GetPropertyValue returns the value of the specified key, if it exists
+func (cfg *PropertiesFile) GetPropertyValue(key string) (string, bool) {
+	return cfg.props.Get(key)
+}
+
+// RemoveProperty removes a property, if it exists
+func (cfg *PropertiesFile) RemoveProperty(key string) {
+	cfg.props.Delete(key)
+}
+
 // SetComments sets comment(s) for the specified key
 func (cfg *PropertiesFile) SetComments(key string, comments []string) {
 	cfg.props.SetComments(key, comments)
@@ -3,7 +3,6 @@ package types
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 
 	"github.com/hashicorp/terraform/helper/schema"
 )
@@ -14,6 +13,7 @@ type RemoteConnection struct {
 	Hostname    string `json:"hostname,omitempty"`
 	Port        int    `json:"port,string,omitempty"`
 	PreventSudo bool   `json:"prevent_sudo,string,omitempty"`
+	Password    string `json:"-"`
 	PrivateKey  string `json:"-"`
 	HostKey     string `json:"-"`
 }
@@ -29,13 +29,13 @@ func ReadRemoteConnectionFromString(data string) (*RemoteConnection, error) {
 }
 
 // ToJSON marshalls the struct to a JSON string
-func (r RemoteConnection) ToJSON() string {
+func (r RemoteConnection) ToJSON() (string, error) {
 	data, err := json.Marshal(r)
 	if err != nil {
-		log.Fatalf("Could not marshall object to JSON: %v", r)
+		return "", fmt.Errorf("could not marshall the connection to %s to JSON: %v", r.Hostname, err)
 	}
 
-	return string(data)
+	return string(data), nil
 }
 
 // SudoPrefix prefixes the specified command with 'sudo', if the RemoteConnection allows it and the user is not root
@@ -64,6 +64,9 @@ func ReadRemoteConnection(list []interface{}) RemoteConnection {
 	if v, ok := ReadBool(data, "prevent_sudo"); ok {
 		conn.PreventSudo = v
 	}
+	if v, ok := ReadString(data, "password"); ok {
+		conn.Password = v
+	}
 	if v, ok := ReadString(data, "private_key"); ok {
 		conn.PrivateKey = v
 	}
@@ -73,6 +76,15 @@ func ReadRemoteConnection(list []interface{}) RemoteConnection {
 	return *conn
 }
 
+// ReadRemoteConnections parses a list of RemoteConnectionSchema resources as a slice of RemoteConnection types
+func ReadRemoteConnections(list []interface{}) []RemoteConnection {
+	conns := make([]RemoteConnection, 0, len(list))
+	for i := range list {
+		conns = append(conns, ReadRemoteConnection(list[i:i+1]))
+	}
+	return conns
+}
+
 // RemoteConnectionSchema holds parameters used to initialize a remote SSH connection
 var RemoteConnectionSchema = &schema.Resource{
 	Schema: map[string]*schema.Schema{
@@ -93,6 +105,12 @@ var RemoteConnectionSchema = &schema.Resource{
 			Optional: true,
 			Default:  false,
 		},
+		"password": {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Default:   "",
+			Sensitive: true,
+		},
 		"private_key": {
 			Type:     schema.TypeString,
 			Optional: true,
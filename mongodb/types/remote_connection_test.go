@@ -0,0 +1,27 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadRemoteConnectionPassword_unit(t *testing.T) {
+	conn := ReadRemoteConnection([]interface{}{map[string]interface{}{
+		"user":     "admin",
+		"hostname": "appliance.example.com",
+		"port":     22,
+		"password": "secret",
+	}})
+	if conn.Password != "secret" {
+		t.Errorf("unexpected password: %s", conn.Password)
+	}
+
+	// the connection is used as the resource ID, which must not contain credentials
+	id, err := conn.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(id, "secret") {
+		t.Errorf("the password was serialized: %s", id)
+	}
+}
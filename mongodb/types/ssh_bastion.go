@@ -18,6 +18,7 @@ func SSHBastionSchema() map[string]*schema.Schema {
 			Type:        schema.TypeString,
 			Optional:    true,
 			Default:     "",
+			Sensitive:   true,
 			Description: "",
 		},
 		"bastion_private_key": {
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// TeamSchema constructs a terraform schema map representing an Ops Manager team
+func TeamSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"org_id": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"usernames": {
+			Type:     schema.TypeSet,
+			Required: true,
+			MinItems: 1,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"team_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mongodb-labs/pcgc/pkg/opsmanager"
+)
+
+// UserSchema constructs a terraform schema map representing an Ops Manager user and its roles
+func UserSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"username": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"password": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+		},
+		"first_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"last_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"email_address": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"role": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem:     UserRoleSchema,
+		},
+		"user_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+// UserRoleSchema holds a single organization or project role, granted to a user
+var UserRoleSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"org_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"project_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"role_name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+	},
+}
+
+// ReadUserRoles parses a list of UserRoleSchema resources as a slice of opsmanager.UserRole types
+func ReadUserRoles(list []interface{}) []opsmanager.UserRole {
+	roles := make([]opsmanager.UserRole, 0, len(list))
+	for _, item := range list {
+		role := opsmanager.UserRole{}
+		data := item.(map[string]interface{})
+		if v, ok := ReadString(data, "org_id"); ok {
+			role.OrgID = v
+		}
+		if v, ok := ReadString(data, "project_id"); ok {
+			role.GroupID = v
+		}
+		if v, ok := ReadString(data, "role_name"); ok {
+			role.RoleName = v
+		}
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+// FlattenUserRoles converts a slice of opsmanager.UserRole types into a list of UserRoleSchema resources
+func FlattenUserRoles(roles []opsmanager.UserRole) []interface{} {
+	list := make([]interface{}, 0, len(roles))
+	for _, role := range roles {
+		list = append(list, map[string]interface{}{
+			"org_id":     role.OrgID,
+			"project_id": role.GroupID,
+			"role_name":  role.RoleName,
+		})
+	}
+	return list
+}
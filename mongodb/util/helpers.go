@@ -1,6 +1,7 @@
 package util
 
 import (
+	"fmt"
 	"log"
 	"regexp"
 	"strings"
@@ -12,18 +13,6 @@ func init() {
 	reValidEmail = regexp.MustCompile(`(?i)^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,32}$`)
 }
 
-// PanicOnError panics when the action is run and results in an error
-func PanicOnError(action func() error) {
-	PanicOnNonNilErr(action())
-}
-
-// PanicOnNonNilErr convenience wrapper which panics when the passed error is not nil
-func PanicOnNonNilErr(err error) {
-	if err != nil {
-		log.Fatalf("err=%v", err)
-	}
-}
-
 // LogError logs any errors returned by the action
 func LogError(action func() error) {
 	LogNonNilError(action())
@@ -75,3 +64,65 @@ func TryExtractFirstLastNameAndEmail(contents string) (firstName string, lastNam
 
 	return
 }
+
+// ParseMongoURIHosts returns the host:port pairs defined in a MongoDB connection string;
+// no hosts are returned for mongodb+srv:// connection strings, since these are resolved through DNS
+func ParseMongoURIHosts(uri string) ([]string, error) {
+	if strings.HasPrefix(uri, "mongodb+srv://") {
+		return nil, nil
+	}
+	if !strings.HasPrefix(uri, "mongodb://") {
+		return nil, fmt.Errorf("the connection string must start with mongodb://")
+	}
+
+	// drop the scheme, the path and options, and the credentials
+	hostList := strings.TrimPrefix(uri, "mongodb://")
+	if i := strings.IndexAny(hostList, "/?"); i >= 0 {
+		hostList = hostList[:i]
+	}
+	if i := strings.LastIndex(hostList, "@"); i >= 0 {
+		hostList = hostList[i+1:]
+	}
+
+	var hosts []string
+	for _, host := range strings.Split(hostList, ",") {
+		if host == "" {
+			return nil, fmt.Errorf("the connection string contains an empty host")
+		}
+		if !strings.Contains(host, ":") {
+			host += ":27017"
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// ShellQuote quotes the passed string for use as a single argument in a shell command
+func ShellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", "'\\''", -1) + "'"
+}
+
+// RedactMongoURI replaces the password in a MongoDB connection string, so that it can be logged or returned in errors
+func RedactMongoURI(uri string) string {
+	i := strings.Index(uri, "://")
+	if i < 0 {
+		return uri
+	}
+	scheme, rest := uri[:i+3], uri[i+3:]
+
+	// the credentials end at the last '@' before the options
+	authority := rest
+	if j := strings.Index(rest, "?"); j >= 0 {
+		authority = rest[:j]
+	}
+	at := strings.LastIndex(authority, "@")
+	if at < 0 {
+		return uri
+	}
+
+	user := rest[:at]
+	if j := strings.Index(user, ":"); j >= 0 {
+		user = user[:j] + ":****"
+	}
+	return scheme + user + rest[at:]
+}